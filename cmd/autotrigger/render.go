@@ -0,0 +1,252 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"sigs.k8s.io/yaml"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+)
+
+// document is one YAML document of an input file.
+type document struct {
+	file string
+	// line is the line of the file the document starts on.
+	line int
+	data []byte
+}
+
+// position returns the file and line of the first line of the document
+// that contains s, or of the document itself.
+func (d document) position(s string) string {
+	for i, line := range strings.Split(string(d.data), "\n") {
+		if strings.Contains(line, s) {
+			return fmt.Sprintf("%s:%d", d.file, d.line+i)
+		}
+	}
+	return fmt.Sprintf("%s:%d", d.file, d.line)
+}
+
+type addressableDocument struct {
+	document
+	addressable *duckv1.AddressableType
+}
+
+// render prints the Triggers made for the labeled Addressables read from the
+// files in args, or stdin, and returns the exit code. Filter annotations that
+// cannot be parsed are reported with their file and line.
+func render(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	namespace := fs.String("namespace", "default", "The namespace of the resources that do not set one.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var docs []document
+	for _, file := range files {
+		var data []byte
+		var err error
+		if file == "-" {
+			file = "<stdin>"
+			data, err = ioutil.ReadAll(stdin)
+		} else {
+			data, err = ioutil.ReadFile(file)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		docs = append(docs, splitDocuments(file, data)...)
+	}
+
+	cfg := config.FromContextOrDefaults(context.Background()).AutoTrigger
+	profiles := make(map[types.NamespacedName]*autotriggerv1alpha1.AutoTriggerProfile)
+	var addressables []addressableDocument
+	failed := false
+	for _, doc := range docs {
+		obj := &duckv1.AddressableType{}
+		if err := yaml.Unmarshal(doc.data, obj); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", doc.position(""), err)
+			failed = true
+			continue
+		}
+		if obj.Namespace == "" {
+			obj.Namespace = *namespace
+		}
+
+		gk := schema.FromAPIVersionAndKind(obj.APIVersion, obj.Kind).GroupKind()
+		switch {
+		case gk == schema.GroupKind{Kind: "ConfigMap"} && obj.Name == config.ConfigName:
+			cm := &corev1.ConfigMap{}
+			if err := yaml.Unmarshal(doc.data, cm); err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", doc.position(""), err)
+				failed = true
+				continue
+			}
+			at, err := config.NewAutoTriggerFromConfigMap(cm)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", doc.position(""), err)
+				failed = true
+				continue
+			}
+			cfg = at
+
+		case gk == autotriggerv1alpha1.Kind("AutoTriggerProfile"):
+			profile := &autotriggerv1alpha1.AutoTriggerProfile{}
+			if err := yaml.Unmarshal(doc.data, profile); err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", doc.position(""), err)
+				failed = true
+				continue
+			}
+			profiles[types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}] = profile
+
+		case resources.AutoTriggerEnabled(obj):
+			addressables = append(addressables, addressableDocument{document: doc, addressable: obj})
+		}
+	}
+
+	var triggers []*eventingv1alpha1.Trigger
+	for _, doc := range addressables {
+		a := doc.addressable
+
+		filters := make([]autotrigger.Filter, 0)
+		for _, name := range resources.ProfileNames(a) {
+			profile, ok := profiles[types.NamespacedName{Namespace: a.Namespace, Name: name}]
+			if !ok {
+				// The profile may well exist in the cluster.
+				fmt.Fprintf(stderr, "%s: warning: %s %s/%s: AutoTriggerProfile %q not found in the input\n",
+					doc.position(resources.ProfileAnnotation), a.Kind, a.Namespace, a.Name, name)
+				continue
+			}
+			filters = append(filters, profile.Spec.Filters...)
+		}
+
+		made, pending, err := resources.MakeTriggers(a, cfg, filters...)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s %s/%s: %v\n", doc.position(resources.FilterAnnotation), a.Kind, a.Namespace, a.Name, err)
+			failed = true
+			continue
+		}
+		for _, filter := range pending {
+			// The Trigger subscribes by URI, which the input does not give.
+			fmt.Fprintf(stderr, "%s: warning: %s %s/%s: no address to subscribe to Broker %s with\n",
+				doc.position(filter.Broker), a.Kind, a.Namespace, a.Name, filter.Broker)
+		}
+		triggers = append(triggers, made...)
+	}
+
+	for i, trigger := range triggers {
+		out, err := marshalTrigger(trigger)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout, "---")
+		}
+		stdout.Write(out)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// splitDocuments splits YAML data on the "---" separator lines, and keeps the
+// line each document starts on. Empty documents are dropped.
+func splitDocuments(file string, data []byte) []document {
+	var docs []document
+	var buf bytes.Buffer
+	start, n := 1, 0
+	flush := func() {
+		if len(bytes.TrimSpace(buf.Bytes())) > 0 {
+			docs = append(docs, document{file: file, line: start, data: append([]byte(nil), buf.Bytes()...)})
+		}
+		buf.Reset()
+		start = n + 1
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		n++
+		line := scanner.Text()
+		if strings.HasPrefix(line, "---") && strings.TrimSpace(strings.TrimPrefix(line, "---")) == "" {
+			flush()
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	flush()
+	return docs
+}
+
+// marshalTrigger returns the Trigger as a YAML manifest, without the fields
+// that are only set by the API server. Addressables read from files have no
+// UID, so the owner references and owner-uid label, which the controller sets,
+// are left out as well.
+func marshalTrigger(trigger *eventingv1alpha1.Trigger) ([]byte, error) {
+	trigger = trigger.DeepCopy()
+	trigger.APIVersion = eventingv1alpha1.SchemeGroupVersion.String()
+	trigger.Kind = "Trigger"
+	if len(trigger.OwnerReferences) > 0 && trigger.OwnerReferences[0].UID == "" {
+		trigger.OwnerReferences = nil
+	}
+	if uid, ok := trigger.Labels[autotrigger.OwnerUIDLabel]; ok && uid == "" {
+		delete(trigger.Labels, autotrigger.OwnerUIDLabel)
+	}
+
+	raw, err := json.Marshal(trigger)
+	if err != nil {
+		return nil, err
+	}
+	manifest := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+	delete(manifest, "status")
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	return yaml.Marshal(manifest)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const service = `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: hello
+  labels:
+    eventing.knative.dev/autotrigger: "true"
+  annotations:
+    trigger.eventing.knative.dev/filter: '[{"type":"dev.example.ping"}]'
+`
+
+func TestRender(t *testing.T) {
+	tests := map[string]struct {
+		in         string
+		wantCode   int
+		wantOut    []string
+		wantStderr []string
+	}{
+		"labeled": {
+			in:       service,
+			wantOut:  []string{"kind: Trigger", "generateName: hello-", "type: dev.example.ping"},
+			wantCode: 0,
+		},
+		"not labeled": {
+			in: `apiVersion: v1
+kind: Service
+metadata:
+  name: plain
+`,
+			wantCode: 0,
+		},
+		"profile": {
+			in: `---
+apiVersion: autotrigger.eventing.knative.dev/v1alpha1
+kind: AutoTriggerProfile
+metadata:
+  name: audit
+spec:
+  filters:
+  - type: dev.example.audit
+---
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: hello
+  labels:
+    eventing.knative.dev/autotrigger: "true"
+  annotations:
+    trigger.eventing.knative.dev/profile: audit, missing
+`,
+			wantOut:    []string{"type: dev.example.audit"},
+			wantStderr: []string{`<stdin>:17: warning: Service default/hello: AutoTriggerProfile "missing" not found`},
+			wantCode:   0,
+		},
+		"broker in another namespace": {
+			in: `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: hello
+  labels:
+    eventing.knative.dev/autotrigger: "true"
+  annotations:
+    trigger.eventing.knative.dev/filter: |
+      [{"type":"dev.example.ping"},
+       {"broker":"events/shared","type":"dev.example.pong"}]
+`,
+			wantOut:    []string{"type: dev.example.ping"},
+			wantStderr: []string{"<stdin>:10: warning: Service default/hello: no address to subscribe to Broker events/shared with"},
+			wantCode:   0,
+		},
+		"bad filter": {
+			in: service + `---
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: broken
+  namespace: prod
+  labels:
+    eventing.knative.dev/autotrigger: "true"
+  annotations:
+    trigger.eventing.knative.dev/filter: '[{"type":}]'
+`,
+			wantOut:    []string{"type: dev.example.ping"},
+			wantStderr: []string{"<stdin>:18: Service prod/broken: failed to parse the filter"},
+			wantCode:   1,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := render(nil, strings.NewReader(tc.in), &stdout, &stderr)
+			if code != tc.wantCode {
+				t.Errorf("render() = %d, wanted %d; stderr: %s", code, tc.wantCode, stderr.String())
+			}
+			for _, want := range tc.wantOut {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout does not contain %q:\n%s", want, stdout.String())
+				}
+			}
+			for _, want := range tc.wantStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("stderr does not contain %q:\n%s", want, stderr.String())
+				}
+			}
+		})
+	}
+}
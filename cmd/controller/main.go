@@ -17,12 +17,97 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/transport"
+	sourcesv1alpha1 "knative.dev/eventing/pkg/apis/sources/v1alpha1"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/system"
+
+	"github.com/n3wscott/autotrigger/pkg/leaderelection"
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/ratelimit"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autosink"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/binding"
 	"github.com/n3wscott/autotrigger/pkg/reconciler/crds"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline"
+	"github.com/n3wscott/autotrigger/pkg/scope"
 
 	// This defines the shared main for injected controllers.
 	"knative.dev/pkg/injection/sharedmain"
 )
 
+var (
+	masterURL   = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	kubeconfig  = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	leaderElect = flag.Bool("leader-elect", true, "Only run the controllers while holding the autotrigger-controller Lease, so that replicas can fail over.")
+	namespaces  = flag.String("namespaces", "", "Comma separated list of the namespaces to watch and act in. All namespaces are watched when empty.")
+	nsSelector  = flag.String("namespace-selector", "", "Label selector of the namespaces to act in, among the ones watched.")
+)
+
 func main() {
-	sharedmain.Main("controller", crds.NewController)
+	flag.Parse()
+
+	cfg, err := sharedmain.GetConfig(*masterURL, *kubeconfig)
+	if err != nil {
+		log.Fatal("Error building kubeconfig", err)
+	}
+
+	// Writes to the eventing APIs share a budget, set in config-autotrigger.
+	budget := ratelimit.NewBudget(metrics.NewStatsReporter())
+	cfg.WrapTransport = transport.Wrappers(cfg.WrapTransport, budget.Wrap)
+
+	ctx := ratelimit.WithBudget(signals.NewContext(), budget)
+
+	nsScope, err := scope.New(*namespaces, *nsSelector)
+	if err != nil {
+		log.Fatal("Error parsing the namespace scope", err)
+	}
+	ctx = nsScope.Context(ctx)
+
+	run := func(ctx context.Context) {
+		sharedmain.MainWithConfig(ctx, "controller", cfg, nsScope.Constructors(cfg,
+			[]injection.ControllerConstructor{crds.NewController},
+			binding.NewController,
+			pipeline.NewSequenceController,
+			pipeline.NewParallelController,
+			autosink.NewControllerConstructor("ContainerSources", sourcesv1alpha1.SchemeGroupVersion.WithResource("containersources"), sourcesv1alpha1.Kind("ContainerSource")),
+			autosink.NewControllerConstructor("CronJobSources", sourcesv1alpha1.SchemeGroupVersion.WithResource("cronjobsources"), sourcesv1alpha1.Kind("CronJobSource")),
+			autosink.NewControllerConstructor("ApiServerSources", sourcesv1alpha1.SchemeGroupVersion.WithResource("apiserversources"), sourcesv1alpha1.Kind("ApiServerSource")),
+		)...)
+	}
+
+	if !*leaderElect {
+		run(ctx)
+		return
+	}
+
+	// Wait for our turn. The controllers, including the ones the crds
+	// controller starts per Addressable kind, are only made once the Lease is
+	// held. They stop, and so does the process, when the Lease is lost; the
+	// replica restarts as a candidate.
+	identity, err := os.Hostname()
+	if err != nil {
+		log.Fatal("Error getting hostname", err)
+	}
+	elector, err := leaderelection.NewElector(kubernetes.NewForConfigOrDie(cfg).CoordinationV1(), leaderelection.Config{
+		Namespace:     system.Namespace(),
+		Name:          "autotrigger-controller",
+		Identity:      identity,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	})
+	if err != nil {
+		log.Fatal("Error setting up leader election", err)
+	}
+	if err := elector.Run(ctx, run); err != nil {
+		log.Fatal("Error waiting for leader election", err)
+	}
 }
@@ -0,0 +1,287 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+)
+
+// addressableLabel marks the CRDs of Addressable kinds, as for the controller.
+const addressableLabel = "duck.knative.dev/addressable"
+
+// kind is an Addressable kind the controller watches.
+type kind struct {
+	gvr   schema.GroupVersionResource
+	gvk   schema.GroupVersionKind
+	names []string
+}
+
+func (k kind) String() string {
+	return k.gvk.GroupKind().String()
+}
+
+// addressableKinds discovers the Addressable kinds the way the controller
+// does, from the labeled CRDs.
+func (p *plugin) addressableKinds() ([]kind, error) {
+	crds, err := p.apiextensionsClient.ApiextensionsV1beta1().CustomResourceDefinitions().List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{addressableLabel: "true"}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var kinds []kind
+	for _, crd := range crds.Items {
+		version := servedVersion(&crd)
+		if version == "" {
+			continue
+		}
+		names := []string{crd.Spec.Names.Kind, crd.Spec.Names.Plural, crd.Spec.Names.Singular, crd.Spec.Names.Plural + "." + crd.Spec.Group}
+		kinds = append(kinds, kind{
+			gvr:   schema.GroupVersionResource{Group: crd.Spec.Group, Version: version, Resource: crd.Spec.Names.Plural},
+			gvk:   schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: crd.Spec.Names.Kind},
+			names: append(names, crd.Spec.Names.ShortNames...),
+		})
+	}
+	return kinds, nil
+}
+
+// servedVersion returns the version to read the objects of the CRD at: its
+// storage version, or the first served one if the storage version is not
+// served. Triggers are tied to the group and kind of their Addressable, so
+// any served version will do.
+func servedVersion(crd *apiextensionsv1beta1.CustomResourceDefinition) string {
+	served := ""
+	for _, v := range crd.Spec.Versions {
+		if !v.Served {
+			continue
+		}
+		if v.Storage {
+			return v.Name
+		}
+		if served == "" {
+			served = v.Name
+		}
+	}
+	return served
+}
+
+// resolve finds the kind and name of a "<kind>/<name>" argument. The kind can
+// be given as in kubectl: its Kind, plural, singular or short name.
+func (p *plugin) resolve(arg string) (kind, string, error) {
+	parts := strings.SplitN(arg, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return kind{}, "", fmt.Errorf("expected <kind>/<name>, got %q", arg)
+	}
+	kinds, err := p.addressableKinds()
+	if err != nil {
+		return kind{}, "", err
+	}
+	for _, k := range kinds {
+		for _, name := range k.names {
+			if strings.EqualFold(name, parts[0]) {
+				return k, parts[1], nil
+			}
+		}
+	}
+	return kind{}, "", fmt.Errorf("%q is not an Addressable kind watched by autotrigger", parts[0])
+}
+
+// listNamespace returns the namespace to list in, "" for all of them.
+func (p *plugin) listNamespace() string {
+	if p.allNamespaces {
+		return metav1.NamespaceAll
+	}
+	return p.namespace
+}
+
+// addressables lists the Addressables of the kind.
+func (p *plugin) addressables(k kind) ([]*duckv1.AddressableType, error) {
+	list, err := p.dynamicClient.Resource(k.gvr).Namespace(p.listNamespace()).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addressables := make([]*duckv1.AddressableType, 0, len(list.Items))
+	for _, u := range list.Items {
+		a := &duckv1.AddressableType{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, a); err != nil {
+			return nil, err
+		}
+		addressables = append(addressables, a)
+	}
+	return addressables, nil
+}
+
+// addressable gets the named Addressable of the kind.
+func (p *plugin) addressable(k kind, name string) (*duckv1.AddressableType, error) {
+	u, err := p.dynamicClient.Resource(k.gvr).Namespace(p.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	a := &duckv1.AddressableType{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ownerKey identifies the Addressable a Trigger was made for.
+type ownerKey struct {
+	gk schema.GroupKind
+	types.NamespacedName
+}
+
+// triggers lists the Triggers made by autotrigger, by the Addressable they
+// were made for. Triggers can be made in other namespaces, so all of them are
+// listed, once per command.
+func (p *plugin) triggers() (map[ownerKey][]eventingv1alpha1.Trigger, error) {
+	if p.owned != nil {
+		return p.owned, nil
+	}
+	list, err := p.eventingClient.EventingV1alpha1().Triggers(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(resources.MakeManagedSelector()).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	triggers := make(map[ownerKey][]eventingv1alpha1.Trigger)
+	for _, trigger := range list.Items {
+		if gk, owner, ok := resources.Owner(&trigger); ok {
+			key := ownerKey{gk: gk, NamespacedName: owner}
+			triggers[key] = append(triggers[key], trigger)
+		}
+	}
+	p.owned = triggers
+	return triggers, nil
+}
+
+// triggersFor lists the Triggers made for the Addressable.
+func (p *plugin) triggersFor(k kind, a *duckv1.AddressableType) ([]eventingv1alpha1.Trigger, error) {
+	triggers, err := p.triggers()
+	if err != nil {
+		return nil, err
+	}
+	return triggers[ownerKey{gk: k.gvk.GroupKind(), NamespacedName: types.NamespacedName{Namespace: a.Namespace, Name: a.Name}}], nil
+}
+
+// bindings lists the AutoTriggerBindings in the namespace. None are returned
+// when the AutoTriggerBinding CRD is not installed.
+func (p *plugin) bindings(namespace string) ([]*autotriggerv1alpha1.AutoTriggerBinding, error) {
+	list, err := p.dynamicClient.Resource(autotriggerv1alpha1.SchemeGroupVersion.WithResource("autotriggerbindings")).Namespace(namespace).List(metav1.ListOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	bindings := make([]*autotriggerv1alpha1.AutoTriggerBinding, 0, len(list.Items))
+	for _, u := range list.Items {
+		binding := &autotriggerv1alpha1.AutoTriggerBinding{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, binding); err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+// boundBy returns the AutoTriggerBindings, out of the given ones, that select
+// the Addressable.
+func boundBy(bindings []*autotriggerv1alpha1.AutoTriggerBinding, a *duckv1.AddressableType) ([]*autotriggerv1alpha1.AutoTriggerBinding, error) {
+	var bound []*autotriggerv1alpha1.AutoTriggerBinding
+	for _, binding := range bindings {
+		if binding.Namespace != a.Namespace {
+			continue
+		}
+		if ok, err := resources.BindingMatches(binding, a); err != nil {
+			return nil, err
+		} else if ok {
+			bound = append(bound, binding)
+		}
+	}
+	return bound, nil
+}
+
+// profile gets the named AutoTriggerProfile, or nil if it does not exist.
+func (p *plugin) profile(namespace, name string) (*autotriggerv1alpha1.AutoTriggerProfile, error) {
+	u, err := p.dynamicClient.Resource(autotriggerv1alpha1.SchemeGroupVersion.WithResource("autotriggerprofiles")).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	profile := &autotriggerv1alpha1.AutoTriggerProfile{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// namespaceDefaults returns the entries of the default filter of the namespace
+// of the Addressable that apply to it. Namespaces the user cannot read have no
+// default filter.
+func (p *plugin) namespaceDefaults(k kind, a *duckv1.AddressableType) ([]autotrigger.Filter, error) {
+	ns, err := p.getNamespace(a.Namespace)
+	if err != nil || ns == nil {
+		return nil, err
+	}
+	return resources.NamespaceDefaultFilters(ns, k.gvk.GroupKind(), a)
+}
+
+// getNamespace gets the namespace, or nil if it does not exist or the user
+// cannot read it.
+func (p *plugin) getNamespace(name string) (*corev1.Namespace, error) {
+	if ns, ok := p.namespaces[name]; ok {
+		return ns, nil
+	}
+	ns, err := p.kubeClient.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+		ns = nil
+	} else if err != nil {
+		return nil, err
+	}
+	if p.namespaces == nil {
+		p.namespaces = make(map[string]*corev1.Namespace)
+	}
+	p.namespaces[name] = ns
+	return ns, nil
+}
+
+// readyCount returns how many of the Triggers are Ready.
+func readyCount(triggers []eventingv1alpha1.Trigger) int {
+	ready := 0
+	for _, trigger := range triggers {
+		if trigger.Status.IsReady() {
+			ready++
+		}
+	}
+	return ready
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+)
+
+func TestServedVersion(t *testing.T) {
+	tests := map[string]struct {
+		versions []apiextensionsv1beta1.CustomResourceDefinitionVersion
+		want     string
+	}{
+		"none served": {
+			versions: []apiextensionsv1beta1.CustomResourceDefinitionVersion{{Name: "v1", Storage: true}},
+		},
+		"storage": {
+			versions: []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v2alpha1", Served: true},
+			},
+			want: "v1",
+		},
+		"storage not served": {
+			versions: []apiextensionsv1beta1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Storage: true},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1", Served: true},
+			},
+			want: "v1beta1",
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			crd := &apiextensionsv1beta1.CustomResourceDefinition{
+				Spec: apiextensionsv1beta1.CustomResourceDefinitionSpec{Versions: tc.versions},
+			}
+			if got := servedVersion(crd); got != tc.want {
+				t.Errorf("servedVersion() = %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/types"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+)
+
+// list prints the Addressables that are labeled, bound, or have Triggers.
+func (p *plugin) list() error {
+	kinds, err := p.addressableKinds()
+	if err != nil {
+		return err
+	}
+	triggers, err := p.triggers()
+	if err != nil {
+		return err
+	}
+	bindings, err := p.bindings(p.listNamespace())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tKIND\tNAME\tFILTERS\tREADY")
+	for _, k := range kinds {
+		addressables, err := p.addressables(k)
+		if err != nil {
+			return err
+		}
+		for _, a := range addressables {
+			bound, err := boundBy(bindings, a)
+			if err != nil {
+				return err
+			}
+			// A default filter that cannot be parsed applies to nothing.
+			defaults, _ := p.namespaceDefaults(k, a)
+			owned := triggers[ownerKey{gk: k.gvk.GroupKind(), NamespacedName: types.NamespacedName{Namespace: a.Namespace, Name: a.Name}}]
+			if !resources.AutoTriggerEnabled(a) && len(bound) == 0 && len(defaults) == 0 && len(owned) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\n", a.Namespace, k, a.Name, filterSources(a, bound, len(defaults) > 0), readyCount(owned), len(owned))
+		}
+	}
+	return w.Flush()
+}
+
+// describe prints the Addressable, where its filters come from, and its
+// Triggers.
+func (p *plugin) describe(arg string) error {
+	k, name, err := p.resolve(arg)
+	if err != nil {
+		return err
+	}
+	a, err := p.addressable(k, name)
+	if err != nil {
+		return err
+	}
+	bindings, err := p.bindings(a.Namespace)
+	if err != nil {
+		return err
+	}
+	bound, err := boundBy(bindings, a)
+	if err != nil {
+		return err
+	}
+	defaults, _ := p.namespaceDefaults(k, a)
+	triggers, err := p.triggersFor(k, a)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", a.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", a.Namespace)
+	fmt.Fprintf(w, "Kind:\t%s\n", k)
+	fmt.Fprintf(w, "AutoTrigger:\t%t\n", resources.AutoTriggerEnabled(a))
+	fmt.Fprintf(w, "Filters:\t%s\n", filterSources(a, bound, len(defaults) > 0))
+	fmt.Fprintf(w, "Triggers:\t%d/%d Ready\n", readyCount(triggers), len(triggers))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(triggers) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(p.out)
+	w = tabwriter.NewWriter(p.out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tBROKER\tFILTER\tREADY\tREASON")
+	for _, trigger := range triggers {
+		ready, reason := readiness(&trigger)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", trigger.Namespace, trigger.Name, trigger.Spec.Broker, filterString(&trigger), ready, reason)
+	}
+	return w.Flush()
+}
+
+// filterSources describes where the filters of the Addressable come from: its
+// filter annotation, its profiles, the bindings that select it and the
+// default filter of its namespace.
+func filterSources(a *duckv1.AddressableType, bound []*autotriggerv1alpha1.AutoTriggerBinding, defaults bool) string {
+	var sources []string
+	if filter, ok := a.Annotations[resources.FilterAnnotation]; ok {
+		sources = append(sources, filter)
+	}
+	for _, name := range resources.ProfileNames(a) {
+		sources = append(sources, "profile:"+name)
+	}
+	for _, binding := range bound {
+		sources = append(sources, "binding:"+binding.Name)
+	}
+	if defaults {
+		sources = append(sources, "namespace:"+a.Namespace)
+	}
+	if len(sources) == 0 {
+		return "-"
+	}
+	return strings.Join(sources, " ")
+}
+
+// filterString prints the filter attributes of the Trigger as sorted
+// key=value pairs.
+func filterString(trigger *eventingv1alpha1.Trigger) string {
+	if trigger.Spec.Filter == nil || trigger.Spec.Filter.Attributes == nil || len(*trigger.Spec.Filter.Attributes) == 0 {
+		return "-"
+	}
+	var pairs []string
+	for k, v := range *trigger.Spec.Filter.Attributes {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// readiness returns the status and reason of the Ready condition of the
+// Trigger.
+func readiness(trigger *eventingv1alpha1.Trigger) (string, string) {
+	cond := trigger.Status.GetCondition(apis.ConditionReady)
+	if cond == nil {
+		return "Unknown", "-"
+	}
+	reason := cond.Reason
+	if reason == "" {
+		reason = "-"
+	}
+	return string(cond.Status), reason
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+)
+
+func TestFilterSources(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		bound       []*autotriggerv1alpha1.AutoTriggerBinding
+		defaults    bool
+		want        string
+	}{
+		"none": {
+			want: "-",
+		},
+		"all": {
+			annotations: map[string]string{
+				resources.FilterAnnotation:  `[{"type":"dev.example.ping"}]`,
+				resources.ProfileAnnotation: "audit, billing",
+			},
+			bound: []*autotriggerv1alpha1.AutoTriggerBinding{{
+				ObjectMeta: metav1.ObjectMeta{Name: "team"},
+			}},
+			defaults: true,
+			want:     `[{"type":"dev.example.ping"}] profile:audit profile:billing binding:team namespace:default`,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			a := &duckv1.AddressableType{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Annotations: tc.annotations}}
+			if got := filterSources(a, tc.bound, tc.defaults); got != tc.want {
+				t.Errorf("filterSources() = %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterString(t *testing.T) {
+	attributes := eventingv1alpha1.TriggerFilterAttributes{"type": "dev.example.ping", "source": "ping"}
+	tests := map[string]struct {
+		filter *eventingv1alpha1.TriggerFilter
+		want   string
+	}{
+		"none": {
+			want: "-",
+		},
+		"attributes": {
+			filter: &eventingv1alpha1.TriggerFilter{Attributes: &attributes},
+			want:   "source=ping,type=dev.example.ping",
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			trigger := &eventingv1alpha1.Trigger{Spec: eventingv1alpha1.TriggerSpec{Filter: tc.filter}}
+			if got := filterString(trigger); got != tc.want {
+				t.Errorf("filterString() = %q, wanted %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+)
+
+const usage = `kubectl autotrigger inspects the Addressables autotrigger makes Triggers for.
+
+Usage:
+  kubectl autotrigger list [-A]
+  kubectl autotrigger describe <kind>/<name>
+  kubectl autotrigger why <kind>/<name>
+
+Commands:
+  list      List the autotriggered Addressables, with their filters and how
+            many of their Triggers are Ready.
+  describe  Show an Addressable and its Triggers.
+  why       Explain why an Addressable has no Triggers, or no Ready ones.
+
+Flags:
+`
+
+// plugin holds the clients and flags shared by the commands.
+type plugin struct {
+	out io.Writer
+
+	namespace       string
+	allNamespaces   bool
+	systemNamespace string
+
+	kubeClient          kubernetes.Interface
+	dynamicClient       dynamic.Interface
+	eventingClient      eventingclientset.Interface
+	apiextensionsClient apiextensionsclientset.Interface
+
+	// namespaces caches the namespaces looked up for their default filter.
+	namespaces map[string]*corev1.Namespace
+	// cfg caches the configuration of the controller.
+	cfg *config.AutoTrigger
+	// owned caches the Triggers made by autotrigger, by the Addressable they
+	// were made for.
+	owned map[ownerKey][]eventingv1alpha1.Trigger
+}
+
+func main() {
+	flags := pflag.NewFlagSet("kubectl-autotrigger", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	flags.StringVar(&loadingRules.ExplicitPath, "kubeconfig", "", "Path to the kubeconfig file to use.")
+	overrides := &clientcmd.ConfigOverrides{}
+	clientcmd.BindOverrideFlags(overrides, flags, clientcmd.RecommendedConfigOverrideFlags(""))
+	allNamespaces := flags.BoolP("all-namespaces", "A", false, "List the Addressables in all namespaces.")
+	systemNamespace := flags.String("system-namespace", "knative-eventing", "The namespace the autotrigger controller runs in.")
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+	p, err := newPlugin(clientConfig, *allNamespaces, *systemNamespace)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	cmd, args := flags.Arg(0), flags.Args()[1:]
+	switch {
+	case cmd == "list" && len(args) == 0:
+		err = p.list()
+	case cmd == "describe" && len(args) == 1:
+		err = p.describe(args[0])
+	case cmd == "why" && len(args) == 1:
+		err = p.why(args[0])
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newPlugin(clientConfig clientcmd.ClientConfig, allNamespaces bool, systemNamespace string) (*plugin, error) {
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &plugin{
+		out:             os.Stdout,
+		namespace:       namespace,
+		allNamespaces:   allNamespaces,
+		systemNamespace: systemNamespace,
+	}
+	if p.kubeClient, err = kubernetes.NewForConfig(cfg); err != nil {
+		return nil, err
+	}
+	if p.dynamicClient, err = dynamic.NewForConfig(cfg); err != nil {
+		return nil, err
+	}
+	if p.eventingClient, err = eventingclientset.NewForConfig(cfg); err != nil {
+		return nil, err
+	}
+	if p.apiextensionsClient, err = apiextensionsclientset.NewForConfig(cfg); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+)
+
+// why prints the reasons the Addressable has no Triggers, or no Ready ones.
+func (p *plugin) why(arg string) error {
+	k, name, err := p.resolve(arg)
+	if err != nil {
+		return err
+	}
+	a, err := p.addressable(k, name)
+	if apierrs.IsNotFound(err) {
+		fmt.Fprintf(p.out, "%s %s/%s does not exist.\n", k, p.namespace, name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	reasons, err := p.explain(k, a)
+	if err != nil {
+		return err
+	}
+	if len(reasons) == 0 {
+		triggers, err := p.triggersFor(k, a)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(p.out, "%s %s/%s has %d/%d Ready Triggers; nothing is in the way.\n", k, a.Namespace, a.Name, readyCount(triggers), len(triggers))
+		return nil
+	}
+	fmt.Fprintf(p.out, "%s %s/%s:\n", k, a.Namespace, a.Name)
+	for _, reason := range reasons {
+		fmt.Fprintf(p.out, "  - %s\n", reason)
+	}
+	return nil
+}
+
+// explain goes through the checks the controller makes before it makes the
+// Triggers of an Addressable, then the Brokers and Triggers themselves, and
+// returns what is in the way. The checks stop at the first one that keeps the
+// controller from making any Trigger.
+func (p *plugin) explain(k kind, a *duckv1.AddressableType) ([]string, error) {
+	if a.DeletionTimestamp != nil {
+		return []string{"it is being deleted"}, nil
+	}
+
+	cfg, err := p.config()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.NamespaceOptIn {
+		ns, err := p.getNamespace(a.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		// A namespace the user cannot read may well have opted in.
+		if ns != nil && !resources.NamespaceEnabled(ns) {
+			return []string{fmt.Sprintf("the controller only acts in the namespaces with the %s label set to \"true\", and namespace %s does not have it", resources.NamespaceEnabledLabel, a.Namespace)}, nil
+		}
+	}
+
+	kinds, err := p.addressableKinds()
+	if err != nil {
+		return nil, err
+	}
+	for _, owner := range a.OwnerReferences {
+		gvk := schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind)
+		for _, other := range kinds {
+			if other.gvk.GroupKind() == gvk.GroupKind() {
+				return []string{fmt.Sprintf("it is owned by %s %s, another Addressable; Triggers are only made for the top most Addressable", other, owner.Name)}, nil
+			}
+		}
+	}
+
+	bindings, err := p.bindings(a.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	bound, err := boundBy(bindings, a)
+	if err != nil {
+		return nil, err
+	}
+	var reasons []string
+	defaults, err := p.namespaceDefaults(k, a)
+	if err != nil {
+		reasons = append(reasons, fmt.Sprintf("the %s annotation of the namespace cannot be parsed: %v", resources.DefaultFilterAnnotation, err))
+	}
+	if !resources.AutoTriggerEnabled(a) && len(bound) == 0 && len(defaults) == 0 {
+		return append(reasons, fmt.Sprintf("the %s label is not \"true\", no AutoTriggerBinding selects it, and its namespace has no default filter for it", resources.AutoTriggerLabel)), nil
+	}
+
+	filters := make([]autotrigger.Filter, 0)
+	for _, name := range resources.ProfileNames(a) {
+		profile, err := p.profile(a.Namespace, name)
+		if err != nil {
+			return nil, err
+		} else if profile == nil {
+			reasons = append(reasons, fmt.Sprintf("the AutoTriggerProfile %q does not exist", name))
+			continue
+		}
+		filters = append(filters, profile.Spec.Filters...)
+	}
+	for _, binding := range bound {
+		filters = append(filters, resources.BindingFilters(binding)...)
+	}
+	filters = append(filters, defaults...)
+
+	desired, pending, err := resources.MakeTriggers(a, cfg, filters...)
+	if err != nil {
+		return append(reasons, fmt.Sprintf("the %s annotation cannot be parsed: %v", resources.FilterAnnotation, err)), nil
+	}
+	for _, filter := range pending {
+		reasons = append(reasons, fmt.Sprintf("it has no address yet, which the Trigger to the Broker %s in another namespace subscribes to", filter.Broker))
+	}
+	if len(desired) == 0 && len(pending) == 0 {
+		return append(reasons, fmt.Sprintf("it has no %s annotation, profile or binding with filters", resources.FilterAnnotation)), nil
+	}
+
+	denials, err := p.denials(a, desired)
+	if err != nil {
+		return nil, err
+	}
+	reasons = append(reasons, denials...)
+
+	for _, trigger := range desired {
+		broker, err := p.eventingClient.EventingV1alpha1().Brokers(trigger.Namespace).Get(trigger.Spec.Broker, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			reasons = append(reasons, fmt.Sprintf("the Broker %s/%s does not exist", trigger.Namespace, trigger.Spec.Broker))
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if !broker.Status.IsReady() {
+			reasons = append(reasons, fmt.Sprintf("the Broker %s/%s is not Ready%s", trigger.Namespace, trigger.Spec.Broker, conditionMessage(broker.Status.GetCondition(apis.ConditionReady))))
+		}
+	}
+
+	triggers, err := p.triggersFor(k, a)
+	if err != nil {
+		return nil, err
+	}
+	if len(triggers) == 0 {
+		reasons = append(reasons, "no Trigger has been made yet; check the controller logs and the events of the Addressable")
+	}
+	for _, trigger := range triggers {
+		if !trigger.Status.IsReady() {
+			reasons = append(reasons, fmt.Sprintf("the Trigger %s/%s is not Ready%s", trigger.Namespace, trigger.Name, conditionMessage(trigger.Status.GetCondition(apis.ConditionReady))))
+		}
+	}
+	return reasons, nil
+}
+
+// denials returns the Triggers of the Addressable denied by an
+// AutoTriggerPolicy.
+func (p *plugin) denials(a *duckv1.AddressableType, triggers []*eventingv1alpha1.Trigger) ([]string, error) {
+	list, err := p.dynamicClient.Resource(autotriggerv1alpha1.SchemeGroupVersion.WithResource("autotriggerpolicies")).List(metav1.ListOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	policies := make([]*autotriggerv1alpha1.AutoTriggerPolicy, 0, len(list.Items))
+	for _, u := range list.Items {
+		policy := &autotriggerv1alpha1.AutoTriggerPolicy{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, policy); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	namespace, err := p.kubeClient.CoreV1().Namespaces().Get(a.Namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	_, denials, invalid := resources.ApplyPolicies(policies, namespace, triggers)
+	reasons := make([]string, 0, len(denials)+len(invalid))
+	for _, policy := range invalid {
+		reasons = append(reasons, fmt.Sprintf("the AutoTriggerPolicy %q is ignored, its namespaceSelector is invalid: %v", policy.Policy.Name, policy.Err))
+	}
+	for _, denial := range denials {
+		reasons = append(reasons, denial.String())
+	}
+	return reasons, nil
+}
+
+func conditionMessage(cond *apis.Condition) string {
+	if cond == nil {
+		return ""
+	}
+	if cond.Message != "" {
+		return fmt.Sprintf(": %s: %s", cond.Reason, cond.Message)
+	}
+	if cond.Reason != "" {
+		return ": " + cond.Reason
+	}
+	return ""
+}
+
+// config returns the configuration of the controller, from the
+// config-autotrigger ConfigMap in the system namespace. The defaults are used
+// when the ConfigMap does not exist or the user cannot read it.
+func (p *plugin) config() (*config.AutoTrigger, error) {
+	if p.cfg != nil {
+		return p.cfg, nil
+	}
+	cm, err := p.kubeClient.CoreV1().ConfigMaps(p.systemNamespace).Get(config.ConfigName, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+		p.cfg = config.FromContextOrDefaults(context.Background()).AutoTrigger
+		return p.cfg, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if p.cfg, err = config.NewAutoTriggerFromConfigMap(cm); err != nil {
+		return nil, fmt.Errorf("the %s ConfigMap in namespace %s cannot be parsed: %v", config.ConfigName, p.systemNamespace, err)
+	}
+	return p.cfg, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AutoTriggerBinding selects Addressables in its namespace by kind and labels
+// and has them treated as if they were labeled for autotrigger, without
+// editing the Addressables themselves.
+type AutoTriggerBinding struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the Addressables selected and the filters applied to them.
+	Spec AutoTriggerBindingSpec `json:"spec,omitempty"`
+
+	// Status lists the Addressables matched and the Triggers created for them.
+	// +optional
+	Status AutoTriggerBindingStatus `json:"status,omitempty"`
+}
+
+// AutoTriggerBindingSpec defines the Addressables selected by an
+// AutoTriggerBinding and the filters applied to them.
+type AutoTriggerBindingSpec struct {
+	// Subject selects the Addressables bound.
+	Subject BindingSubject `json:"subject"`
+
+	// Filters is the list of filter entries, in the same form as the entries
+	// of the trigger.eventing.knative.dev/filter annotation. An empty list
+	// subscribes to every event on the default Broker.
+	// +optional
+	Filters []autotrigger.Filter `json:"filters,omitempty"`
+}
+
+// BindingSubject selects Addressables of a kind by labels.
+type BindingSubject struct {
+	// APIVersion of the Addressables.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the Addressables.
+	Kind string `json:"kind"`
+
+	// Selector limits the Addressables to those with matching labels. A nil
+	// selector matches every Addressable of the kind.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// AutoTriggerBindingStatus is the observed state of an AutoTriggerBinding.
+type AutoTriggerBindingStatus struct {
+	// ObservedGeneration is the generation of the spec the status describes.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Subjects are the Addressables matched by the binding.
+	// +optional
+	Subjects []BoundSubject `json:"subjects,omitempty"`
+}
+
+// BoundSubject is an Addressable matched by an AutoTriggerBinding.
+type BoundSubject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+
+	// Triggers are the names of the Triggers created for the Addressable.
+	// Triggers in another namespace are given as namespace/name.
+	// +optional
+	Triggers []string `json:"triggers,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AutoTriggerBindingList is a collection of AutoTriggerBindings.
+type AutoTriggerBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []AutoTriggerBinding `json:"items"`
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AutoTriggerPolicy restricts what the Addressables in the namespaces it
+// selects may subscribe to.
+type AutoTriggerPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the restrictions of the policy.
+	Spec AutoTriggerPolicySpec `json:"spec,omitempty"`
+}
+
+// AutoTriggerPolicySpec defines the restrictions of an AutoTriggerPolicy.
+type AutoTriggerPolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to. If left
+	// unspecified, the policy applies to all namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// AllowedBrokers is the list of Brokers that may be subscribed to, either
+	// as "name" for a Broker in the Addressable's namespace or as
+	// "namespace/name". Entries may be glob patterns. If left unspecified,
+	// any Broker is allowed.
+	// +optional
+	AllowedBrokers []string `json:"allowedBrokers,omitempty"`
+
+	// AllowedTypes is the list of glob patterns of CloudEvent types that may
+	// be subscribed to. Subscribing to any type needs "*". If left
+	// unspecified, any type is allowed.
+	// +optional
+	AllowedTypes []string `json:"allowedTypes,omitempty"`
+
+	// DeniedTypes is the list of glob patterns of CloudEvent types that may
+	// not be subscribed to. When set, subscribing to any type is denied.
+	// +optional
+	DeniedTypes []string `json:"deniedTypes,omitempty"`
+
+	// MaxTriggers is the maximum number of Triggers a single Addressable may
+	// have. If left unspecified, there is no limit.
+	// +optional
+	MaxTriggers *int32 `json:"maxTriggers,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AutoTriggerPolicyList is a collection of AutoTriggerPolicies.
+type AutoTriggerPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []AutoTriggerPolicy `json:"items"`
+}
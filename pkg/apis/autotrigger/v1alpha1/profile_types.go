@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AutoTriggerProfile is a named list of filter entries that Addressables in
+// its namespace can reference instead of repeating them in annotations.
+type AutoTriggerProfile struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the filters of the profile.
+	Spec AutoTriggerProfileSpec `json:"spec,omitempty"`
+}
+
+// AutoTriggerProfileSpec defines the filters of an AutoTriggerProfile.
+type AutoTriggerProfileSpec struct {
+	// Filters is the list of filter entries, in the same form as the entries
+	// of the trigger.eventing.knative.dev/filter annotation.
+	Filters []autotrigger.Filter `json:"filters,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AutoTriggerProfileList is a collection of AutoTriggerProfiles.
+type AutoTriggerProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []AutoTriggerProfile `json:"items"`
+}
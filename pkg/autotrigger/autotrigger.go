@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package autotrigger is the Go API of autotrigger, for the programs that
+// embed it: the filter entries of an Addressable, the Triggers synthesized
+// from them, and the changes that bring existing Triggers in line. The
+// controllers are built on it.
+package autotrigger
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// Label turns autotrigger on for an Addressable when "true".
+	Label = "eventing.knative.dev/autotrigger"
+
+	// FilterAnnotation holds the JSON list of filter entries of an
+	// Addressable, one Trigger per entry.
+	FilterAnnotation = "trigger.eventing.knative.dev/filter"
+	// ProfileAnnotation holds a comma separated list of the names of the
+	// AutoTriggerProfiles an Addressable uses.
+	ProfileAnnotation = "trigger.eventing.knative.dev/profile"
+
+	// DefaultBroker is the Broker used when a filter does not name one.
+	DefaultBroker = "default"
+
+	// ReservedPrefix is the prefix of the labels autotrigger sets on the
+	// Triggers it makes. Keys with it are never propagated.
+	ReservedPrefix = "autotrigger.eventing.knative.dev/"
+
+	// ManagedByLabel is set to ManagedBy on every Trigger autotrigger makes.
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedBy      = "autotrigger"
+
+	// Every Trigger is tied to its Addressable by these labels, as owner
+	// references cannot cross namespaces and the labels of the Addressable
+	// itself may change.
+	OwnerNamespaceLabel = ReservedPrefix + "owner-namespace"
+	OwnerNameLabel      = ReservedPrefix + "owner-name"
+	OwnerKindLabel      = ReservedPrefix + "owner-kind"
+	OwnerUIDLabel       = ReservedPrefix + "owner-uid"
+)
+
+// Enabled reports whether the Label of the Addressable turns autotrigger on.
+func Enabled(a *duckv1.AddressableType) bool {
+	return strings.EqualFold(a.Labels[Label], "true")
+}
+
+// OwnerSelector returns the labels that select the Triggers made for the
+// named Addressable of the given kind.
+func OwnerSelector(namespace, name string, gk schema.GroupKind) map[string]string {
+	return map[string]string{
+		OwnerNamespaceLabel: namespace,
+		OwnerNameLabel:      name,
+		OwnerKindLabel:      gk.String(),
+	}
+}
+
+// OwnerLabels returns the labels that tie a Trigger to its Addressable.
+func OwnerLabels(a *duckv1.AddressableType) map[string]string {
+	labels := OwnerSelector(a.Namespace, a.Name, a.GroupVersionKind().GroupKind())
+	labels[OwnerUIDLabel] = string(a.UID)
+	return labels
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotrigger
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// Plan is what it takes to bring the existing Triggers of an Addressable in
+// line with the desired ones.
+type Plan struct {
+	// Keep are the existing Triggers that are as desired.
+	Keep []*eventingv1alpha1.Trigger
+	// Update are copies of existing Triggers on the Broker of a desired one,
+	// changed to match it.
+	Update []*eventingv1alpha1.Trigger
+	// Create are the desired Triggers no existing one can be changed into.
+	Create []*eventingv1alpha1.Trigger
+	// Delete are the existing Triggers that are not desired.
+	Delete []*eventingv1alpha1.Trigger
+}
+
+// Diff pairs the existing Triggers with the desired ones, as made by
+// Synthesize. The Triggers that are as desired are kept first, so that the
+// ones that drifted are only paired with what is left. A drifted Trigger is
+// updated when it is on the same Broker as a desired one, since the Broker of
+// a Trigger cannot be changed. Labels and annotations added to a Trigger by
+// others are left alone.
+func Diff(existing, desired []*eventingv1alpha1.Trigger) Plan {
+	plan := Plan{}
+	remaining := append([]*eventingv1alpha1.Trigger(nil), existing...)
+
+	drifted := []*eventingv1alpha1.Trigger(nil)
+	for _, want := range desired {
+		var trigger *eventingv1alpha1.Trigger
+		remaining, trigger = extractTriggerLike(remaining, want)
+		if trigger == nil {
+			drifted = append(drifted, want)
+			continue
+		}
+		plan.Keep = append(plan.Keep, trigger)
+	}
+
+	for _, want := range drifted {
+		var trigger *eventingv1alpha1.Trigger
+		remaining, trigger = extractTriggerFor(remaining, want)
+		if trigger == nil {
+			plan.Create = append(plan.Create, want)
+			continue
+		}
+		trigger = trigger.DeepCopy()
+		trigger.Spec = want.Spec
+		trigger.Labels = mergeMaps(trigger.Labels, want.Labels)
+		trigger.Annotations = mergeMaps(trigger.Annotations, want.Annotations)
+		plan.Update = append(plan.Update, trigger)
+	}
+
+	if len(remaining) > 0 {
+		plan.Delete = remaining
+	}
+	return plan
+}
+
+// Adopt returns a copy of the Trigger, taken over for the desired one. It
+// gains the owner references, labels and annotations of the desired Trigger,
+// and keeps its spec and the labels and annotations added by others.
+func Adopt(trigger, desired *eventingv1alpha1.Trigger) *eventingv1alpha1.Trigger {
+	trigger = trigger.DeepCopy()
+	trigger.OwnerReferences = append(trigger.OwnerReferences, desired.OwnerReferences...)
+	trigger.Labels = mergeMaps(trigger.Labels, desired.Labels)
+	trigger.Annotations = mergeMaps(trigger.Annotations, desired.Annotations)
+	return trigger
+}
+
+// SemanticEquals reports whether the Trigger matches the desired one.
+// Labels and annotations added to the Trigger by others are ignored.
+func SemanticEquals(desired, trigger *eventingv1alpha1.Trigger) bool {
+	return desired.Namespace == trigger.Namespace &&
+		equality.Semantic.DeepEqual(desired.Spec, trigger.Spec) &&
+		containsAll(trigger.Labels, desired.Labels) &&
+		containsAll(trigger.Annotations, desired.Annotations)
+}
+
+func containsAll(m, subset map[string]string) bool {
+	for k, v := range subset {
+		if got, ok := m[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+func extractTriggerLike(triggers []*eventingv1alpha1.Trigger, like *eventingv1alpha1.Trigger) ([]*eventingv1alpha1.Trigger, *eventingv1alpha1.Trigger) {
+	for i, trigger := range triggers {
+		if SemanticEquals(like, trigger) {
+			return append(triggers[:i], triggers[i+1:]...), trigger
+		}
+	}
+	return triggers, nil
+}
+
+// extractTriggerFor finds a Trigger on the same Broker as the desired one.
+func extractTriggerFor(triggers []*eventingv1alpha1.Trigger, desired *eventingv1alpha1.Trigger) ([]*eventingv1alpha1.Trigger, *eventingv1alpha1.Trigger) {
+	for i, trigger := range triggers {
+		if trigger.Namespace == desired.Namespace && trigger.Spec.Broker == desired.Spec.Broker {
+			return append(triggers[:i], triggers[i+1:]...), trigger
+		}
+	}
+	return triggers, nil
+}
+
+// mergeMaps returns m with the overrides set, as a copy if there are any.
+func mergeMaps(m, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return m
+	}
+	merged := make(map[string]string, len(m)+len(overrides))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
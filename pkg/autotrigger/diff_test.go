@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotrigger
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+func trigger(name, broker, eventType string, labels map[string]string) *eventingv1alpha1.Trigger {
+	attributes := eventingv1alpha1.TriggerFilterAttributes{"type": eventType}
+	return &eventingv1alpha1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			Labels:    labels,
+		},
+		Spec: eventingv1alpha1.TriggerSpec{
+			Broker: broker,
+			Filter: &eventingv1alpha1.TriggerFilter{Attributes: &attributes},
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	owned := map[string]string{ManagedByLabel: ManagedBy}
+
+	ping := trigger("", "default", "ping", owned)
+	pong := trigger("", "default", "pong", owned)
+	other := trigger("", "other", "ping", owned)
+
+	pingNow := trigger("ping-1", "default", "ping", map[string]string{ManagedByLabel: ManagedBy, "team": "a"})
+	drifted := trigger("ping-2", "default", "pang", map[string]string{"team": "a"})
+	stale := trigger("stale-1", "stale", "ping", owned)
+
+	tests := map[string]struct {
+		existing []*eventingv1alpha1.Trigger
+		desired  []*eventingv1alpha1.Trigger
+		want     Plan
+	}{
+		"nothing": {},
+		"create": {
+			desired: []*eventingv1alpha1.Trigger{ping, other},
+			want:    Plan{Create: []*eventingv1alpha1.Trigger{ping, other}},
+		},
+		"keep, with labels added by others": {
+			existing: []*eventingv1alpha1.Trigger{pingNow},
+			desired:  []*eventingv1alpha1.Trigger{ping},
+			want:     Plan{Keep: []*eventingv1alpha1.Trigger{pingNow}},
+		},
+		"update the drifted trigger on the same broker": {
+			existing: []*eventingv1alpha1.Trigger{drifted},
+			desired:  []*eventingv1alpha1.Trigger{pong},
+			want: Plan{Update: []*eventingv1alpha1.Trigger{
+				trigger("ping-2", "default", "pong", map[string]string{ManagedByLabel: ManagedBy, "team": "a"}),
+			}},
+		},
+		"keep before pairing drifted triggers": {
+			existing: []*eventingv1alpha1.Trigger{drifted, pingNow},
+			desired:  []*eventingv1alpha1.Trigger{pong, ping},
+			want: Plan{
+				Keep: []*eventingv1alpha1.Trigger{pingNow},
+				Update: []*eventingv1alpha1.Trigger{
+					trigger("ping-2", "default", "pong", map[string]string{ManagedByLabel: ManagedBy, "team": "a"}),
+				},
+			},
+		},
+		"delete": {
+			existing: []*eventingv1alpha1.Trigger{pingNow, stale},
+			desired:  []*eventingv1alpha1.Trigger{ping},
+			want: Plan{
+				Keep:   []*eventingv1alpha1.Trigger{pingNow},
+				Delete: []*eventingv1alpha1.Trigger{stale},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			existing := append([]*eventingv1alpha1.Trigger(nil), tc.existing...)
+			got := Diff(tc.existing, tc.desired)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Diff() (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(existing, tc.existing); diff != "" {
+				t.Errorf("Diff() changed the existing Triggers (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestAdopt(t *testing.T) {
+	ref := metav1.OwnerReference{APIVersion: "serving.knative.dev/v1", Kind: "Service", Name: "svc", UID: "svc-uid"}
+
+	desired := trigger("", "default", "ping", map[string]string{ManagedByLabel: ManagedBy})
+	desired.Annotations = map[string]string{"note": "made"}
+	desired.OwnerReferences = []metav1.OwnerReference{ref}
+
+	byHand := trigger("ping-1", "default", "ping", map[string]string{"team": "a"})
+	byHand.Annotations = map[string]string{"note": "by hand", "other": "kept"}
+	before := byHand.DeepCopy()
+
+	want := trigger("ping-1", "default", "ping", map[string]string{ManagedByLabel: ManagedBy, "team": "a"})
+	want.Annotations = map[string]string{"note": "made", "other": "kept"}
+	want.OwnerReferences = []metav1.OwnerReference{ref}
+
+	if diff := cmp.Diff(want, Adopt(byHand, desired)); diff != "" {
+		t.Errorf("Adopt() (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(before, byHand); diff != "" {
+		t.Errorf("Adopt() changed the Trigger (-want, +got): %s", diff)
+	}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotrigger
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+)
+
+// The keys of a filter entry in JSON.
+const (
+	brokerKey     = "broker"
+	typeKey       = "type"
+	sourceKey     = "source"
+	subjectKey    = "subject"
+	extensionsKey = "extensions"
+)
+
+var filterKeys = []string{brokerKey, typeKey, sourceKey, subjectKey, extensionsKey}
+
+// maxAttributeNameLength is the length CloudEvents attribute names should
+// not exceed.
+const maxAttributeNameLength = 20
+
+// attributeName matches the names CloudEvents allows for attributes.
+var attributeName = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// Filter is a filter entry: the Broker to subscribe to and the attributes the
+// events have to match. In JSON it is an object such as
+// {"broker":"events/default","type":"dev.example.ping","extensions":{"region":"eu"}}.
+// Attributes that are empty match any value.
+type Filter struct {
+	// Broker is the Broker to subscribe to, as "name" for a Broker in the
+	// namespace of the Addressable or "namespace/name". DefaultBroker is
+	// used when it is empty.
+	Broker string `json:"broker,omitempty"`
+	// Type is the type of the events.
+	Type string `json:"type,omitempty"`
+	// Source is the source of the events.
+	Source string `json:"source,omitempty"`
+	// Subject is the subject of the events.
+	Subject string `json:"subject,omitempty"`
+	// Extensions are the values of the extension attributes of the events,
+	// by attribute name.
+	Extensions map[string]string `json:"extensions,omitempty"`
+
+	// unknown are the keys of the JSON form that are not part of a Filter.
+	// They are kept for Validate to report rather than failing to decode, so
+	// that a bad entry in a resource does not keep the others from being
+	// listed.
+	unknown []string
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Filter) UnmarshalJSON(b []byte) error {
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*f = Filter{}
+	for key, value := range raw {
+		var err error
+		switch key {
+		case brokerKey:
+			err = json.Unmarshal(value, &f.Broker)
+		case typeKey:
+			err = json.Unmarshal(value, &f.Type)
+		case sourceKey:
+			err = json.Unmarshal(value, &f.Source)
+		case subjectKey:
+			err = json.Unmarshal(value, &f.Subject)
+		case extensionsKey:
+			err = json.Unmarshal(value, &f.Extensions)
+		default:
+			f.unknown = append(f.unknown, key)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %q: %v", key, err)
+		}
+	}
+	sort.Strings(f.unknown)
+	return nil
+}
+
+// DeepCopyInto copies the Filter into out.
+func (f *Filter) DeepCopyInto(out *Filter) {
+	*out = *f
+	if f.Extensions != nil {
+		out.Extensions = make(map[string]string, len(f.Extensions))
+		for k, v := range f.Extensions {
+			out.Extensions[k] = v
+		}
+	}
+	if f.unknown != nil {
+		out.unknown = append([]string(nil), f.unknown...)
+	}
+}
+
+// DeepCopy returns a copy of the Filter.
+func (f *Filter) DeepCopy() *Filter {
+	if f == nil {
+		return nil
+	}
+	out := new(Filter)
+	f.DeepCopyInto(out)
+	return out
+}
+
+// BrokerRef returns the namespace and name of the Broker to subscribe to, for
+// an Addressable in the namespace.
+func (f Filter) BrokerRef(namespace string) (string, string) {
+	if f.Broker == "" {
+		return namespace, DefaultBroker
+	}
+	if parts := strings.SplitN(f.Broker, "/", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return namespace, f.Broker
+}
+
+// Attributes returns the attributes the events have to match, by CloudEvents
+// attribute name.
+func (f Filter) Attributes() map[string]string {
+	attributes := make(map[string]string, len(f.Extensions)+3)
+	for k, v := range f.Extensions {
+		attributes[k] = v
+	}
+	for k, v := range map[string]string{typeKey: f.Type, sourceKey: f.Source, subjectKey: f.Subject} {
+		if v != "" {
+			attributes[k] = v
+		}
+	}
+	return attributes
+}
+
+// TriggerFilter returns the filter of the Trigger made for the entry.
+func (f Filter) TriggerFilter() *eventingv1alpha1.TriggerFilter {
+	attributes := eventingv1alpha1.TriggerFilterAttributes(f.Attributes())
+	return &eventingv1alpha1.TriggerFilter{
+		Attributes: &attributes,
+	}
+}
+
+// Validate checks that the Broker of the entry can be subscribed to, that it
+// has no unknown keys and that its extensions are named as CloudEvents
+// requires: lower case letters and digits, no more than 20 of them.
+func (f Filter) Validate() error {
+	var errs []string
+	for _, key := range f.unknown {
+		errs = append(errs, unknownKey(key))
+	}
+	if err := f.validateBroker(); err != "" {
+		errs = append(errs, err)
+	}
+	names := make([]string, 0, len(f.Extensions))
+	for name := range f.Extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := validateExtensionName(name); err != "" {
+			errs = append(errs, fmt.Sprintf("invalid extension %q: %s", name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (f Filter) validateBroker() string {
+	if f.Broker == "" {
+		return ""
+	}
+	var errs []string
+	switch parts := strings.Split(f.Broker, "/"); len(parts) {
+	case 1:
+		errs = validation.IsDNS1123Subdomain(parts[0])
+	case 2:
+		errs = append(validation.IsDNS1123Label(parts[0]), validation.IsDNS1123Subdomain(parts[1])...)
+	default:
+		errs = []string{`must be "name" or "namespace/name"`}
+	}
+	if len(errs) > 0 {
+		return fmt.Sprintf("invalid broker %q: %s", f.Broker, strings.Join(errs, ", "))
+	}
+	return ""
+}
+
+func validateExtensionName(name string) string {
+	switch name {
+	case typeKey, sourceKey, subjectKey:
+		return fmt.Sprintf("set it with the %q key instead", name)
+	}
+	var errs []string
+	if !attributeName.MatchString(name) {
+		errs = append(errs, "must consist of lower case letters (a-z) and digits (0-9)")
+	}
+	if len(name) > maxAttributeNameLength {
+		errs = append(errs, fmt.Sprintf("must be no more than %d characters", maxAttributeNameLength))
+	}
+	return strings.Join(errs, ", ")
+}
+
+// unknownKey describes an unknown key of a filter entry, with the key it is
+// most likely a typo of, if any.
+func unknownKey(key string) string {
+	for _, known := range filterKeys {
+		if editDistance(strings.ToLower(key), known) <= 1 {
+			return fmt.Sprintf("unknown key %q, did you mean %q?", key, known)
+		}
+	}
+	return fmt.Sprintf("unknown key %q, extension attributes go in %q", key, extensionsKey)
+}
+
+// editDistance is the number of insertions, deletions, substitutions and
+// swaps of adjacent characters it takes to turn a into b.
+func editDistance(a, b string) int {
+	// d[i][j] is the distance between a[:i] and b[:j].
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minOf(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minOf(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minOf(first int, rest ...int) int {
+	for _, n := range rest {
+		if n < first {
+			first = n
+		}
+	}
+	return first
+}
+
+// ValidateFilters checks every entry, naming the first invalid one.
+func ValidateFilters(filters []Filter) error {
+	for i, f := range filters {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("filter entry %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// ParseFilters parses and validates the value of the FilterAnnotation. An
+// empty value or list subscribes to every event on the default Broker.
+func ParseFilters(raw string) ([]Filter, error) {
+	if raw == "" || raw == "[]" || raw == "[{}]" {
+		return []Filter{{}}, nil
+	}
+	filters := make([]Filter, 0)
+	if err := json.Unmarshal([]byte(raw), &filters); err != nil {
+		return nil, fmt.Errorf("failed to parse the filter: %v", err)
+	}
+	if err := ValidateFilters(filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
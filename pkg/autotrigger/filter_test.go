@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotrigger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFilters(t *testing.T) {
+	tests := map[string]struct {
+		raw     string
+		want    []Filter
+		wantErr string
+	}{
+		"empty": {
+			want: []Filter{{}},
+		},
+		"empty list": {
+			raw:  "[]",
+			want: []Filter{{}},
+		},
+		"empty entry": {
+			raw:  "[{}]",
+			want: []Filter{{}},
+		},
+		"entries": {
+			raw: `[{"type":"dev.example.ping"},{"broker":"events/shared","source":"example","subject":"orders"}]`,
+			want: []Filter{{
+				Type: "dev.example.ping",
+			}, {
+				Broker:  "events/shared",
+				Source:  "example",
+				Subject: "orders",
+			}},
+		},
+		"extensions": {
+			raw: `[{"type":"dev.example.ping","extensions":{"region":"eu","tier2":"gold"}}]`,
+			want: []Filter{{
+				Type:       "dev.example.ping",
+				Extensions: map[string]string{"region": "eu", "tier2": "gold"},
+			}},
+		},
+		"not json": {
+			raw:     `[{"type"`,
+			wantErr: "failed to parse the filter",
+		},
+		"not a string": {
+			raw:     `[{"type":1}]`,
+			wantErr: `failed to parse the filter: invalid "type"`,
+		},
+		"invalid broker": {
+			raw:     `[{"broker":"Events/shared"}]`,
+			wantErr: `filter entry 0: invalid broker "Events/shared"`,
+		},
+		"too many slashes": {
+			raw:     `[{"broker":"a/b/c"}]`,
+			wantErr: `filter entry 0: invalid broker "a/b/c": must be "name" or "namespace/name"`,
+		},
+		"typo": {
+			raw:     `[{"type":"dev.example.ping"},{"tpye":"dev.example.pong"}]`,
+			wantErr: `filter entry 1: unknown key "tpye", did you mean "type"?`,
+		},
+		"wrong case": {
+			raw:     `[{"Source":"example"}]`,
+			wantErr: `filter entry 0: unknown key "Source", did you mean "source"?`,
+		},
+		"extension outside extensions": {
+			raw:     `[{"region":"eu"}]`,
+			wantErr: `filter entry 0: unknown key "region", extension attributes go in "extensions"`,
+		},
+		"upper case extension": {
+			raw:     `[{"extensions":{"Region":"eu"}}]`,
+			wantErr: `filter entry 0: invalid extension "Region": must consist of lower case letters (a-z) and digits (0-9)`,
+		},
+		"extension with a dash": {
+			raw:     `[{"extensions":{"my-region":"eu"}}]`,
+			wantErr: `filter entry 0: invalid extension "my-region": must consist of lower case letters (a-z) and digits (0-9)`,
+		},
+		"long extension": {
+			raw:     `[{"extensions":{"averyveryverylongname":"eu"}}]`,
+			wantErr: `filter entry 0: invalid extension "averyveryverylongname": must be no more than 20 characters`,
+		},
+		"type as an extension": {
+			raw:     `[{"extensions":{"type":"dev.example.ping"}}]`,
+			wantErr: `filter entry 0: invalid extension "type": set it with the "type" key instead`,
+		},
+		"every error": {
+			raw:     `[{"broker":"a/b/c","sorce":"example","extensions":{"Region":"eu"}}]`,
+			wantErr: `filter entry 0: unknown key "sorce", did you mean "source"?; invalid broker "a/b/c": must be "name" or "namespace/name"; invalid extension "Region": must consist of lower case letters (a-z) and digits (0-9)`,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseFilters(tc.raw)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Errorf("ParseFilters() = %v, wanted an error", got)
+				} else if !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Errorf("ParseFilters() = %v, wanted %s", err, tc.wantErr)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("ParseFilters() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(Filter{})); diff != "" {
+				t.Errorf("ParseFilters() (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestFilterJSON(t *testing.T) {
+	want := Filter{
+		Broker:     "shared",
+		Type:       "dev.example.ping",
+		Extensions: map[string]string{"region": "eu"},
+	}
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	if got, wantJSON := string(b), `{"broker":"shared","type":"dev.example.ping","extensions":{"region":"eu"}}`; got != wantJSON {
+		t.Errorf("Marshal() = %s, wanted %s", got, wantJSON)
+	}
+	var got Filter
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(Filter{})); diff != "" {
+		t.Errorf("Unmarshal() (-want, +got): %s", diff)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	f := Filter{
+		Broker:     "shared",
+		Type:       "dev.example.ping",
+		Subject:    "orders",
+		Extensions: map[string]string{"region": "eu"},
+	}
+	want := map[string]string{"type": "dev.example.ping", "subject": "orders", "region": "eu"}
+	if diff := cmp.Diff(want, f.Attributes()); diff != "" {
+		t.Errorf("Attributes() (-want, +got): %s", diff)
+	}
+}
+
+func TestBrokerRef(t *testing.T) {
+	tests := map[string]struct {
+		broker        string
+		wantNamespace string
+		wantName      string
+	}{
+		"default":         {wantNamespace: "ns", wantName: DefaultBroker},
+		"local":           {broker: "shared", wantNamespace: "ns", wantName: "shared"},
+		"other namespace": {broker: "events/shared", wantNamespace: "events", wantName: "shared"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			namespace, broker := Filter{Broker: tc.broker}.BrokerRef("ns")
+			if namespace != tc.wantNamespace || broker != tc.wantName {
+				t.Errorf("BrokerRef() = %s/%s, wanted %s/%s", namespace, broker, tc.wantNamespace, tc.wantName)
+			}
+		})
+	}
+}
@@ -14,12 +14,14 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package names
+package autotrigger
 
 import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
-func Trigger(addressable *duckv1.AddressableType) string {
+// TriggerName is the name the names of the Triggers of the Addressable are
+// generated from.
+func TriggerName(addressable *duckv1.AddressableType) string {
 	return addressable.Name
 }
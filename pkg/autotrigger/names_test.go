@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package names
+package autotrigger
 
 import (
 	"testing"
@@ -23,30 +23,14 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
-func TestTrigger(t *testing.T) {
-	tests := []struct {
-		name    string
-		service *duckv1.AddressableType
-		f       func(*duckv1.AddressableType) string
-		want    string
-	}{{
-		name: "Trigger",
-		service: &duckv1.AddressableType{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "foo",
-				Namespace: "default",
-			},
+func TestTriggerName(t *testing.T) {
+	addressable := &duckv1.AddressableType{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
 		},
-		f:    Trigger,
-		want: "foo",
-	}}
-
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			got := test.f(test.service)
-			if got != test.want {
-				t.Errorf("%s() = %v, wanted %v", test.name, got, test.want)
-			}
-		})
+	}
+	if got, want := TriggerName(addressable), "foo"; got != want {
+		t.Errorf("TriggerName() = %v, wanted %v", got, want)
 	}
 }
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotrigger
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/ptr"
+)
+
+// Config is how Triggers are synthesized.
+type Config struct {
+	// Labels selects the labels of an Addressable copied onto its Triggers.
+	Labels Propagation
+	// Annotations selects the annotations of an Addressable copied onto its
+	// Triggers.
+	Annotations Propagation
+}
+
+// Propagation selects keys by prefix. A key is propagated when it starts with
+// one of the Include prefixes and none of the Exclude prefixes. The prefix
+// "*" matches every key.
+type Propagation struct {
+	Include []string
+	Exclude []string
+}
+
+// Filter returns the entries of in that are propagated.
+func (p Propagation) Filter(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		if strings.HasPrefix(k, ReservedPrefix) || k == ManagedByLabel {
+			continue
+		}
+		if hasPrefix(k, p.Include) && !hasPrefix(k, p.Exclude) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func hasPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix == "*" || strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Synthesize makes the Triggers for the Addressable: one per entry of its
+// FilterAnnotation and per extra entry, such as the ones of the profiles it
+// references or the bindings that select it. A Trigger in another namespace
+// subscribes by URI, so it is left out until the Addressable has an address;
+// the filters of the Triggers left out are returned as pending.
+func Synthesize(addressable *duckv1.AddressableType, cfg Config, extra ...Filter) (triggers []*eventingv1alpha1.Trigger, pending []Filter, err error) {
+	rawFilter, ok := addressable.Annotations[FilterAnnotation]
+	if !ok && len(extra) == 0 {
+		return nil, nil, nil
+	}
+
+	filters := make([]Filter, 0, len(extra))
+	for i, filter := range extra {
+		if err := filter.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("extra filter entry %d: %v", i, err)
+		}
+		filters = append(filters, filter)
+	}
+	if ok {
+		annotated, err := ParseFilters(rawFilter)
+		if err != nil {
+			return nil, nil, err
+		}
+		filters = append(filters, annotated...)
+	}
+
+	triggers = make([]*eventingv1alpha1.Trigger, 0, len(filters))
+
+	subscriber := &v1alpha1.Destination{
+		Ref: &corev1.ObjectReference{
+			APIVersion: addressable.APIVersion,
+			Kind:       addressable.Kind,
+			Name:       addressable.Name,
+		},
+	}
+
+	for _, filter := range filters {
+		namespace, broker := filter.BrokerRef(addressable.Namespace)
+		t := &eventingv1alpha1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: TriggerName(addressable) + "-",
+				Namespace:    namespace,
+				Labels:       triggerLabels(addressable, cfg),
+				Annotations:  triggerAnnotations(addressable, cfg),
+			},
+			Spec: eventingv1alpha1.TriggerSpec{
+				Broker:     broker,
+				Filter:     filter.TriggerFilter(),
+				Subscriber: subscriber,
+			},
+		}
+		if namespace == addressable.Namespace {
+			t.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion:         addressable.APIVersion,
+				Kind:               addressable.Kind,
+				Name:               addressable.Name,
+				UID:                addressable.UID,
+				BlockOwnerDeletion: ptr.Bool(true),
+				Controller:         ptr.Bool(true),
+			}}
+		} else {
+			// A ref cannot point across namespaces, so subscribe by URI once
+			// the Addressable has an address.
+			if addressable.Status.Address == nil || addressable.Status.Address.URL == nil {
+				pending = append(pending, filter)
+				continue
+			}
+			t.Spec.Subscriber = &v1alpha1.Destination{
+				URI: addressable.Status.Address.URL,
+			}
+		}
+		triggers = append(triggers, t)
+	}
+
+	return triggers, pending, nil
+}
+
+// triggerLabels are the labels propagated from the Addressable, the
+// ManagedByLabel and the owner labels.
+func triggerLabels(a *duckv1.AddressableType, cfg Config) map[string]string {
+	labels := cfg.Labels.Filter(a.Labels)
+	labels[ManagedByLabel] = ManagedBy
+	for k, v := range OwnerLabels(a) {
+		labels[k] = v
+	}
+	return labels
+}
+
+// triggerAnnotations are the annotations propagated from the Addressable.
+func triggerAnnotations(a *duckv1.AddressableType, cfg Config) map[string]string {
+	annotations := cfg.Annotations.Filter(a.Annotations)
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
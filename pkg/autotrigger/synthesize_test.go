@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotrigger
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestSynthesize(t *testing.T) {
+	addressable := &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "svc",
+			UID:       "svc-uid",
+			Labels:    map[string]string{Label: "true", "team": "a"},
+			Annotations: map[string]string{
+				FilterAnnotation: `[{"type":"dev.example.ping"}]`,
+				"owner":          "someone",
+			},
+		},
+	}
+	cfg := Config{
+		Labels:      Propagation{Include: []string{"*"}, Exclude: []string{Label}},
+		Annotations: Propagation{Include: []string{"owner"}},
+	}
+
+	got, pending, err := Synthesize(addressable, cfg, Filter{Broker: "shared"}, Filter{Broker: "events/shared"})
+	if err != nil {
+		t.Fatalf("Synthesize() = %v", err)
+	}
+
+	meta := func(broker string, attributes eventingv1alpha1.TriggerFilterAttributes) *eventingv1alpha1.Trigger {
+		return &eventingv1alpha1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "svc-",
+				Namespace:    "ns",
+				Labels: map[string]string{
+					"team":              "a",
+					ManagedByLabel:      ManagedBy,
+					OwnerNamespaceLabel: "ns",
+					OwnerNameLabel:      "svc",
+					OwnerKindLabel:      "Service.serving.knative.dev",
+					OwnerUIDLabel:       "svc-uid",
+				},
+				Annotations: map[string]string{"owner": "someone"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion:         "serving.knative.dev/v1",
+					Kind:               "Service",
+					Name:               "svc",
+					UID:                "svc-uid",
+					BlockOwnerDeletion: ptr.Bool(true),
+					Controller:         ptr.Bool(true),
+				}},
+			},
+			Spec: eventingv1alpha1.TriggerSpec{
+				Broker: broker,
+				Filter: &eventingv1alpha1.TriggerFilter{Attributes: &attributes},
+				Subscriber: &v1alpha1.Destination{
+					Ref: &corev1.ObjectReference{
+						APIVersion: "serving.knative.dev/v1",
+						Kind:       "Service",
+						Name:       "svc",
+					},
+				},
+			},
+		}
+	}
+	want := []*eventingv1alpha1.Trigger{
+		meta("shared", eventingv1alpha1.TriggerFilterAttributes{}),
+		meta(DefaultBroker, eventingv1alpha1.TriggerFilterAttributes{"type": "dev.example.ping"}),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Synthesize() (-want, +got): %s", diff)
+	}
+	// Without an address, the Trigger in another namespace cannot subscribe.
+	if diff := cmp.Diff([]Filter{{Broker: "events/shared"}}, pending, cmp.AllowUnexported(Filter{})); diff != "" {
+		t.Errorf("Synthesize() pending (-want, +got): %s", diff)
+	}
+
+	if _, _, err := Synthesize(addressable, cfg, Filter{Broker: "a/b/c"}); err == nil {
+		t.Error("Synthesize() with an invalid extra entry = nil, wanted an error")
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+
+	v1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	listers "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+)
+
+// AutoTriggerBindingInformer provides access to a shared informer and lister for
+// AutoTriggerBindings.
+type AutoTriggerBindingInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() listers.AutoTriggerBindingLister
+}
+
+type autoTriggerBindingInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+// NewAutoTriggerBindingInformer constructs a new informer for AutoTriggerBindings in
+// all namespaces.
+func NewAutoTriggerBindingInformer(client dynamic.Interface, resyncPeriod time.Duration) AutoTriggerBindingInformer {
+	return NewFilteredAutoTriggerBindingInformer(client, metav1.NamespaceAll, resyncPeriod)
+}
+
+// NewFilteredAutoTriggerBindingInformer constructs a new informer for AutoTriggerBindings in
+// the namespace.
+func NewFilteredAutoTriggerBindingInformer(client dynamic.Interface, namespace string, resyncPeriod time.Duration) AutoTriggerBindingInformer {
+	return &autoTriggerBindingInformer{
+		informer: newInformer(
+			client.Resource(v1alpha1.SchemeGroupVersion.WithResource("autotriggerbindings")).Namespace(namespace),
+			&v1alpha1.AutoTriggerBinding{},
+			&v1alpha1.AutoTriggerBindingList{},
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		),
+	}
+}
+
+func (f *autoTriggerBindingInformer) Informer() cache.SharedIndexInformer {
+	return f.informer
+}
+
+func (f *autoTriggerBindingInformer) Lister() listers.AutoTriggerBindingLister {
+	return listers.NewAutoTriggerBindingLister(f.informer.GetIndexer())
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+
+	v1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	listers "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+)
+
+// AutoTriggerPolicyInformer provides access to a shared informer and lister for
+// AutoTriggerPolicies.
+type AutoTriggerPolicyInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() listers.AutoTriggerPolicyLister
+}
+
+type autoTriggerPolicyInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+// NewAutoTriggerPolicyInformer constructs a new informer for AutoTriggerPolicies.
+func NewAutoTriggerPolicyInformer(client dynamic.Interface, resyncPeriod time.Duration) AutoTriggerPolicyInformer {
+	return &autoTriggerPolicyInformer{
+		informer: newInformer(
+			client.Resource(v1alpha1.SchemeGroupVersion.WithResource("autotriggerpolicies")),
+			&v1alpha1.AutoTriggerPolicy{},
+			&v1alpha1.AutoTriggerPolicyList{},
+			resyncPeriod,
+			cache.Indexers{},
+		),
+	}
+}
+
+func (f *autoTriggerPolicyInformer) Informer() cache.SharedIndexInformer {
+	return f.informer
+}
+
+func (f *autoTriggerPolicyInformer) Lister() listers.AutoTriggerPolicyLister {
+	return listers.NewAutoTriggerPolicyLister(f.informer.GetIndexer())
+}
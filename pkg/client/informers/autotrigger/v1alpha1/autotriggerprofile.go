@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+
+	v1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	listers "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+)
+
+// AutoTriggerProfileInformer provides access to a shared informer and lister for
+// AutoTriggerProfiles.
+type AutoTriggerProfileInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() listers.AutoTriggerProfileLister
+}
+
+type autoTriggerProfileInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+// NewAutoTriggerProfileInformer constructs a new informer for AutoTriggerProfiles in
+// all namespaces.
+func NewAutoTriggerProfileInformer(client dynamic.Interface, resyncPeriod time.Duration) AutoTriggerProfileInformer {
+	return NewFilteredAutoTriggerProfileInformer(client, metav1.NamespaceAll, resyncPeriod)
+}
+
+// NewFilteredAutoTriggerProfileInformer constructs a new informer for AutoTriggerProfiles in
+// the namespace.
+func NewFilteredAutoTriggerProfileInformer(client dynamic.Interface, namespace string, resyncPeriod time.Duration) AutoTriggerProfileInformer {
+	return &autoTriggerProfileInformer{
+		informer: newInformer(
+			client.Resource(v1alpha1.SchemeGroupVersion.WithResource("autotriggerprofiles")).Namespace(namespace),
+			&v1alpha1.AutoTriggerProfile{},
+			&v1alpha1.AutoTriggerProfileList{},
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		),
+	}
+}
+
+func (f *autoTriggerProfileInformer) Informer() cache.SharedIndexInformer {
+	return f.informer
+}
+
+func (f *autoTriggerProfileInformer) Lister() listers.AutoTriggerProfileLister {
+	return listers.NewAutoTriggerProfileLister(f.informer.GetIndexer())
+}
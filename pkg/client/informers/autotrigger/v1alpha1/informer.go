@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 holds informers for the autotrigger v1alpha1 API. They are
+// built on the dynamic client, so the API needs no generated clientset.
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis/duck"
+)
+
+// newInformer creates a SharedIndexInformer that converts the unstructured
+// objects from the dynamic client into the types of obj and listObj.
+func newInformer(client dynamic.ResourceInterface, obj, listObj runtime.Object, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			ul, err := client.List(opts)
+			if err != nil {
+				return nil, err
+			}
+			res := listObj.DeepCopyObject()
+			if err := duck.FromUnstructured(ul, res); err != nil {
+				return nil, err
+			}
+			return res, nil
+		},
+		WatchFunc: duck.AsStructuredWatcher(client.Watch, obj),
+	}
+	return cache.NewSharedIndexInformer(lw, obj, resyncPeriod, indexers)
+}
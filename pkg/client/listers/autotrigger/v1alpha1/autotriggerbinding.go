@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	v1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+)
+
+// AutoTriggerBindingLister helps list AutoTriggerBindings.
+type AutoTriggerBindingLister interface {
+	// List lists all AutoTriggerBindings in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerBinding, err error)
+	// AutoTriggerBindings returns an object that can list and get AutoTriggerBindings.
+	AutoTriggerBindings(namespace string) AutoTriggerBindingNamespaceLister
+}
+
+// autoTriggerBindingLister implements the AutoTriggerBindingLister interface.
+type autoTriggerBindingLister struct {
+	indexer cache.Indexer
+}
+
+// NewAutoTriggerBindingLister returns a new AutoTriggerBindingLister.
+func NewAutoTriggerBindingLister(indexer cache.Indexer) AutoTriggerBindingLister {
+	return &autoTriggerBindingLister{indexer: indexer}
+}
+
+// List lists all AutoTriggerBindings in the indexer.
+func (s *autoTriggerBindingLister) List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerBinding, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.AutoTriggerBinding))
+	})
+	return ret, err
+}
+
+// AutoTriggerBindings returns an object that can list and get AutoTriggerBindings.
+func (s *autoTriggerBindingLister) AutoTriggerBindings(namespace string) AutoTriggerBindingNamespaceLister {
+	return autoTriggerBindingNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// AutoTriggerBindingNamespaceLister helps list and get AutoTriggerBindings.
+type AutoTriggerBindingNamespaceLister interface {
+	// List lists all AutoTriggerBindings in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerBinding, err error)
+	// Get retrieves the AutoTriggerBinding from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.AutoTriggerBinding, error)
+}
+
+// autoTriggerBindingNamespaceLister implements the AutoTriggerBindingNamespaceLister
+// interface.
+type autoTriggerBindingNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all AutoTriggerBindings in the indexer for a given namespace.
+func (s autoTriggerBindingNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerBinding, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.AutoTriggerBinding))
+	})
+	return ret, err
+}
+
+// Get retrieves the AutoTriggerBinding from the indexer for a given namespace and name.
+func (s autoTriggerBindingNamespaceLister) Get(name string) (*v1alpha1.AutoTriggerBinding, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("autotriggerbinding"), name)
+	}
+	return obj.(*v1alpha1.AutoTriggerBinding), nil
+}
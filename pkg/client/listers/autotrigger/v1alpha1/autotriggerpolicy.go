@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 holds listers for the autotrigger v1alpha1 API.
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	v1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+)
+
+// AutoTriggerPolicyLister helps list AutoTriggerPolicies.
+type AutoTriggerPolicyLister interface {
+	// List lists all AutoTriggerPolicies in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerPolicy, err error)
+	// Get retrieves the AutoTriggerPolicy from the index for a given name.
+	Get(name string) (*v1alpha1.AutoTriggerPolicy, error)
+}
+
+// autoTriggerPolicyLister implements the AutoTriggerPolicyLister interface.
+type autoTriggerPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewAutoTriggerPolicyLister returns a new AutoTriggerPolicyLister.
+func NewAutoTriggerPolicyLister(indexer cache.Indexer) AutoTriggerPolicyLister {
+	return &autoTriggerPolicyLister{indexer: indexer}
+}
+
+// List lists all AutoTriggerPolicies in the indexer.
+func (s *autoTriggerPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.AutoTriggerPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the AutoTriggerPolicy from the index for a given name.
+func (s *autoTriggerPolicyLister) Get(name string) (*v1alpha1.AutoTriggerPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("autotriggerpolicy"), name)
+	}
+	return obj.(*v1alpha1.AutoTriggerPolicy), nil
+}
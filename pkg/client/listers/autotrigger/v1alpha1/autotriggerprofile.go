@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	v1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+)
+
+// AutoTriggerProfileLister helps list AutoTriggerProfiles.
+type AutoTriggerProfileLister interface {
+	// List lists all AutoTriggerProfiles in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerProfile, err error)
+	// AutoTriggerProfiles returns an object that can list and get AutoTriggerProfiles.
+	AutoTriggerProfiles(namespace string) AutoTriggerProfileNamespaceLister
+}
+
+// autoTriggerProfileLister implements the AutoTriggerProfileLister interface.
+type autoTriggerProfileLister struct {
+	indexer cache.Indexer
+}
+
+// NewAutoTriggerProfileLister returns a new AutoTriggerProfileLister.
+func NewAutoTriggerProfileLister(indexer cache.Indexer) AutoTriggerProfileLister {
+	return &autoTriggerProfileLister{indexer: indexer}
+}
+
+// List lists all AutoTriggerProfiles in the indexer.
+func (s *autoTriggerProfileLister) List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerProfile, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.AutoTriggerProfile))
+	})
+	return ret, err
+}
+
+// AutoTriggerProfiles returns an object that can list and get AutoTriggerProfiles.
+func (s *autoTriggerProfileLister) AutoTriggerProfiles(namespace string) AutoTriggerProfileNamespaceLister {
+	return autoTriggerProfileNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// AutoTriggerProfileNamespaceLister helps list and get AutoTriggerProfiles.
+type AutoTriggerProfileNamespaceLister interface {
+	// List lists all AutoTriggerProfiles in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerProfile, err error)
+	// Get retrieves the AutoTriggerProfile from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.AutoTriggerProfile, error)
+}
+
+// autoTriggerProfileNamespaceLister implements the AutoTriggerProfileNamespaceLister
+// interface.
+type autoTriggerProfileNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all AutoTriggerProfiles in the indexer for a given namespace.
+func (s autoTriggerProfileNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.AutoTriggerProfile, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.AutoTriggerProfile))
+	})
+	return ret, err
+}
+
+// Get retrieves the AutoTriggerProfile from the indexer for a given namespace and name.
+func (s autoTriggerProfileNamespaceLister) Get(name string) (*v1alpha1.AutoTriggerProfile, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("autotriggerprofile"), name)
+	}
+	return obj.(*v1alpha1.AutoTriggerProfile), nil
+}
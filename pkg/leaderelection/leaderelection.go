@@ -0,0 +1,246 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leaderelection lets replicas of the controller take turns holding a
+// coordination.k8s.io Lease, so that only one of them acts at a time.
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+// Config configures the election for a Lease.
+type Config struct {
+	// Namespace and Name of the Lease.
+	Namespace string
+	Name      string
+
+	// Identity of this replica, usually the name of its Pod.
+	Identity string
+
+	// LeaseDuration is how long the other replicas wait after the last renewal
+	// before taking the Lease over.
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader keeps trying to renew the Lease
+	// before giving up leadership.
+	RenewDeadline time.Duration
+	// RetryPeriod is the time between attempts to acquire or renew the Lease.
+	RetryPeriod time.Duration
+}
+
+// Elector acquires and renews a Lease.
+type Elector struct {
+	config Config
+	client coordinationv1client.LeasesGetter
+	clock  system.Clock
+
+	// The last record seen and when it was seen, by our clock, so that the
+	// clocks of the replicas do not have to agree.
+	observedRenewTime *metav1.MicroTime
+	observedTime      time.Time
+}
+
+// NewElector creates an Elector for the configured Lease.
+func NewElector(client coordinationv1client.LeasesGetter, config Config) (*Elector, error) {
+	if config.Namespace == "" || config.Name == "" || config.Identity == "" {
+		return nil, fmt.Errorf("leader election needs a Lease namespace, name and identity")
+	}
+	if config.LeaseDuration <= config.RenewDeadline {
+		return nil, fmt.Errorf("lease duration %v must be greater than the renew deadline %v", config.LeaseDuration, config.RenewDeadline)
+	}
+	if config.RenewDeadline <= config.RetryPeriod {
+		return nil, fmt.Errorf("renew deadline %v must be greater than the retry period %v", config.RenewDeadline, config.RetryPeriod)
+	}
+	return &Elector{
+		config: config,
+		client: client,
+		clock:  system.RealClock{},
+	}, nil
+}
+
+// Elect blocks until the Lease is acquired or ctx is done. The returned
+// context is cancelled once leadership is lost or ctx is done, at which point
+// the Lease is released if it is still held.
+func (e *Elector) Elect(ctx context.Context) (context.Context, error) {
+	logger := logging.FromContext(ctx)
+
+	logger.Infof("waiting for Lease %s/%s", e.config.Namespace, e.config.Name)
+	for {
+		if ok, err := e.tryAcquireOrRenew(); err != nil {
+			logger.Errorf("failed to acquire Lease %s/%s: %v", e.config.Namespace, e.config.Name, err)
+		} else if ok {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(e.config.RetryPeriod):
+		}
+	}
+	logger.Infof("acquired Lease %s/%s as %s", e.config.Namespace, e.config.Name, e.config.Identity)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+		e.renew(leaderCtx)
+	}()
+	return leaderCtx, nil
+}
+
+// Run calls run once the Lease is acquired, with a context that is cancelled
+// once leadership is lost. A replica that never acquires the Lease returns when
+// ctx is done, without calling run. The Lease is released when run returns.
+func (e *Elector) Run(ctx context.Context, run func(context.Context)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	leaderCtx, err := e.Elect(ctx)
+	if err != nil {
+		return err
+	}
+	run(leaderCtx)
+	return nil
+}
+
+// renew renews the Lease until ctx is done or the Lease could not be renewed
+// within the renew deadline.
+func (e *Elector) renew(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	ticker := time.NewTicker(e.config.RetryPeriod)
+	defer ticker.Stop()
+
+	renewed := e.clock.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			e.release(logger)
+			return
+		case <-ticker.C:
+		}
+
+		ok, err := e.tryAcquireOrRenew()
+		if err != nil {
+			logger.Errorf("failed to renew Lease %s/%s: %v", e.config.Namespace, e.config.Name, err)
+		}
+		if ok {
+			renewed = e.clock.Now()
+		} else if e.clock.Now().Sub(renewed) > e.config.RenewDeadline {
+			logger.Errorf("lost Lease %s/%s", e.config.Namespace, e.config.Name)
+			return
+		}
+	}
+}
+
+// tryAcquireOrRenew takes the Lease if it is free or expired, or renews it if
+// it is already ours. It reports whether the Lease is held afterwards.
+func (e *Elector) tryAcquireOrRenew() (bool, error) {
+	leases := e.client.Leases(e.config.Namespace)
+	now := metav1.NewMicroTime(e.clock.Now())
+
+	lease, err := leases.Get(e.config.Name, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: e.config.Namespace,
+				Name:      e.config.Name,
+			},
+		}
+		e.hold(lease, now)
+		if _, err := leases.Create(lease); err != nil {
+			if apierrs.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+
+	if lease.Spec.RenewTime == nil || e.observedRenewTime == nil || !lease.Spec.RenewTime.Equal(e.observedRenewTime) {
+		e.observedRenewTime = lease.Spec.RenewTime
+		e.observedTime = e.clock.Now()
+	}
+	if holder != "" && holder != e.config.Identity && e.observedTime.Add(e.duration(lease)).After(e.clock.Now()) {
+		// Held by another replica that is still renewing it.
+		return false, nil
+	}
+
+	lease = lease.DeepCopy()
+	if holder != e.config.Identity {
+		transitions := int32(1)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions += *lease.Spec.LeaseTransitions
+		}
+		lease.Spec.LeaseTransitions = &transitions
+		e.hold(lease, now)
+	} else {
+		lease.Spec.RenewTime = &now
+	}
+	if _, err := leases.Update(lease); err != nil {
+		if apierrs.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// release gives up the Lease, so that another replica can take it over
+// without waiting for it to expire.
+func (e *Elector) release(logger *zap.SugaredLogger) {
+	leases := e.client.Leases(e.config.Namespace)
+	lease, err := leases.Get(e.config.Name, metav1.GetOptions{})
+	if err != nil || lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != e.config.Identity {
+		return
+	}
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = nil
+	if _, err := leases.Update(lease); err == nil {
+		logger.Infof("released Lease %s/%s", e.config.Namespace, e.config.Name)
+	}
+}
+
+func (e *Elector) hold(lease *coordinationv1.Lease, now metav1.MicroTime) {
+	seconds := int32(e.config.LeaseDuration / time.Second)
+	lease.Spec.HolderIdentity = &e.config.Identity
+	lease.Spec.LeaseDurationSeconds = &seconds
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+}
+
+func (e *Elector) duration(lease *coordinationv1.Lease) time.Duration {
+	if lease.Spec.LeaseDurationSeconds != nil {
+		return time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return e.config.LeaseDuration
+}
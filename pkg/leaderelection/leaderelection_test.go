@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+// fakeLeases keeps a single Lease in memory.
+type fakeLeases struct {
+	coordinationv1client.LeaseInterface
+
+	mu    sync.Mutex
+	lease *coordinationv1.Lease
+}
+
+func (f *fakeLeases) Leases(string) coordinationv1client.LeaseInterface {
+	return f
+}
+
+func (f *fakeLeases) Get(name string, _ metav1.GetOptions) (*coordinationv1.Lease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lease == nil {
+		return nil, apierrs.NewNotFound(coordinationv1.Resource("leases"), name)
+	}
+	return f.lease.DeepCopy(), nil
+}
+
+func (f *fakeLeases) Create(lease *coordinationv1.Lease) (*coordinationv1.Lease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lease != nil {
+		return nil, apierrs.NewAlreadyExists(coordinationv1.Resource("leases"), lease.Name)
+	}
+	f.lease = lease.DeepCopy()
+	return lease, nil
+}
+
+func (f *fakeLeases) Update(lease *coordinationv1.Lease) (*coordinationv1.Lease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lease = lease.DeepCopy()
+	return lease, nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newElector(t *testing.T, leases *fakeLeases, clock *fakeClock, identity string) *Elector {
+	e, err := NewElector(leases, Config{
+		Namespace:     "knative-eventing",
+		Name:          "autotrigger-controller",
+		Identity:      identity,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewElector() = %v", err)
+	}
+	e.clock = clock
+	return e
+}
+
+func TestTryAcquireOrRenew(t *testing.T) {
+	leases := &fakeLeases{}
+	clock := &fakeClock{now: time.Now()}
+	a := newElector(t, leases, clock, "a")
+	b := newElector(t, leases, clock, "b")
+
+	if ok, err := a.tryAcquireOrRenew(); err != nil || !ok {
+		t.Fatalf("a.tryAcquireOrRenew() = %v, %v, wanted true", ok, err)
+	}
+	if ok, err := b.tryAcquireOrRenew(); err != nil || ok {
+		t.Fatalf("b.tryAcquireOrRenew() = %v, %v, wanted false while a holds the Lease", ok, err)
+	}
+
+	// a keeps renewing, so b keeps waiting.
+	clock.now = clock.now.Add(10 * time.Second)
+	if ok, err := a.tryAcquireOrRenew(); err != nil || !ok {
+		t.Fatalf("a.tryAcquireOrRenew() = %v, %v, wanted true", ok, err)
+	}
+	clock.now = clock.now.Add(10 * time.Second)
+	if ok, err := b.tryAcquireOrRenew(); err != nil || ok {
+		t.Fatalf("b.tryAcquireOrRenew() = %v, %v, wanted false after a renewed", ok, err)
+	}
+
+	// a stops renewing, so b takes over once the Lease expires.
+	clock.now = clock.now.Add(16 * time.Second)
+	if ok, err := b.tryAcquireOrRenew(); err != nil || !ok {
+		t.Fatalf("b.tryAcquireOrRenew() = %v, %v, wanted true after the Lease expired", ok, err)
+	}
+	if got := *leases.lease.Spec.HolderIdentity; got != "b" {
+		t.Errorf("holder = %q, wanted %q", got, "b")
+	}
+	if got := *leases.lease.Spec.LeaseTransitions; got != 1 {
+		t.Errorf("transitions = %d, wanted 1", got)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	leases := &fakeLeases{}
+	clock := &fakeClock{now: time.Now()}
+	a := newElector(t, leases, clock, "a")
+	b := newElector(t, leases, clock, "b")
+
+	if ok, err := a.tryAcquireOrRenew(); err != nil || !ok {
+		t.Fatalf("a.tryAcquireOrRenew() = %v, %v, wanted true", ok, err)
+	}
+	a.release(zap.NewNop().Sugar())
+
+	// A released Lease is taken over without waiting for it to expire.
+	if ok, err := b.tryAcquireOrRenew(); err != nil || !ok {
+		t.Fatalf("b.tryAcquireOrRenew() = %v, %v, wanted true after a released the Lease", ok, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	leases := &fakeLeases{}
+	clock := &fakeClock{now: time.Now()}
+	a := newElector(t, leases, clock, "a")
+
+	ran := false
+	err := a.Run(context.Background(), func(ctx context.Context) {
+		ran = ctx.Err() == nil
+	})
+	if err != nil || !ran {
+		t.Errorf("a.Run() = %v, ran %v, wanted run called while the Lease is held", err, ran)
+	}
+}
+
+func TestRunNotLeader(t *testing.T) {
+	leases := &fakeLeases{}
+	clock := &fakeClock{now: time.Now()}
+	a := newElector(t, leases, clock, "a")
+	b := newElector(t, leases, clock, "b")
+
+	if ok, err := a.tryAcquireOrRenew(); err != nil || !ok {
+		t.Fatalf("a.tryAcquireOrRenew() = %v, %v, wanted true", ok, err)
+	}
+
+	// run makes the controllers, including the ones the crds controller
+	// starts per Addressable kind, so b must not call it while a leads.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := b.Run(ctx, func(context.Context) {
+		t.Error("b.Run() called run while a holds the Lease")
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("b.Run() = %v, wanted %v", err, context.DeadlineExceeded)
+	}
+}
@@ -24,17 +24,11 @@ import (
 
 const (
 	ObservabilityConfigName = "config-observability"
-	metricsDomain           = "knative.dev/eventing/autotrigger"
 )
 
-// UpdateLogLevelFromConfigMap returns a helper func that can be used to update the exporter
-// when a config map is updated
-func NewObserverLoggingDecorator(component string) metrics.ObserverDecorator {
-	return metrics.NewObserverLoggingDecorator(metricsDomain, component)
-}
-
 // UpdateExporterFromConfigMap returns a helper func that can be used to update the exporter
-// when a config map is updated
+// when a config map is updated. The metrics domain is read from the METRICS_DOMAIN
+// environment variable.
 func UpdateExporterFromConfigMap(component string, logger *zap.SugaredLogger) func(configMap *corev1.ConfigMap) {
-	return metrics.UpdateExporterFromConfigMap(metricsDomain, component, logger)
+	return metrics.UpdateExporterFromConfigMap(component, logger)
 }
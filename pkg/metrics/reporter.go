@@ -0,0 +1,278 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+const (
+	// Operations reported with ReportTriggerOperation and
+	// ReportDryRunOperation. Only the sink of a Source is patched.
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationAdopt  = "adopt"
+	OperationPatch  = "patch"
+
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+var (
+	reconcileLatencyM = stats.Float64(
+		"addressable_reconcile_latency",
+		"Latency of the reconciliation of an Addressable",
+		stats.UnitMilliseconds)
+
+	requeueDelayM = stats.Float64(
+		"requeue_delay",
+		"Delay the workqueue rate limiter put on an Addressable before it is reconciled again",
+		stats.UnitMilliseconds)
+
+	writeBudgetWaitM = stats.Float64(
+		"write_budget_wait",
+		"Time an eventing API write waited for the write budget",
+		stats.UnitMilliseconds)
+
+	triggerOperationsM = stats.Int64(
+		"trigger_operations",
+		"Number of Triggers created, updated, deleted or adopted",
+		stats.UnitDimensionless)
+
+	dryRunOperationsM = stats.Int64(
+		"dry_run_operations",
+		"Number of writes to Triggers, pipelines, EventTypes or Sources skipped in dry-run mode",
+		stats.UnitDimensionless)
+
+	filterParseFailuresM = stats.Int64(
+		"filter_parse_failures",
+		"Number of filter annotations that could not be parsed",
+		stats.UnitDimensionless)
+
+	watchedResourcesM = stats.Int64(
+		"watched_resources",
+		"Number of Addressable resources watched",
+		stats.UnitDimensionless)
+
+	managedTriggersM = stats.Int64(
+		"managed_triggers",
+		"Number of Triggers made by autotrigger",
+		stats.UnitDimensionless)
+
+	notReadyTriggersM = stats.Int64(
+		"not_ready_triggers",
+		"Number of Triggers made by autotrigger that are not Ready",
+		stats.UnitDimensionless)
+
+	resourceKey  = tag.MustNewKey("resource")
+	resultKey    = tag.MustNewKey("result")
+	operationKey = tag.MustNewKey("operation")
+	namespaceKey = tag.MustNewKey("namespace_name")
+)
+
+func init() {
+	if err := view.Register(
+		&view.View{
+			Description: reconcileLatencyM.Description(),
+			Measure:     reconcileLatencyM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 100000)...),
+			TagKeys:     []tag.Key{resourceKey, resultKey},
+		},
+		&view.View{
+			Name:        "addressable_reconcile_count",
+			Description: "Number of reconciliations of Addressables",
+			Measure:     reconcileLatencyM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{resourceKey, resultKey},
+		},
+		&view.View{
+			Description: requeueDelayM.Description(),
+			Measure:     requeueDelayM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 1000000)...),
+			TagKeys:     []tag.Key{resourceKey},
+		},
+		&view.View{
+			Description: writeBudgetWaitM.Description(),
+			Measure:     writeBudgetWaitM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 100000)...),
+			TagKeys:     []tag.Key{resourceKey},
+		},
+		&view.View{
+			Description: triggerOperationsM.Description(),
+			Measure:     triggerOperationsM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{resourceKey, operationKey},
+		},
+		&view.View{
+			Description: dryRunOperationsM.Description(),
+			Measure:     dryRunOperationsM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{resourceKey, operationKey},
+		},
+		&view.View{
+			Description: filterParseFailuresM.Description(),
+			Measure:     filterParseFailuresM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{resourceKey},
+		},
+		&view.View{
+			Description: watchedResourcesM.Description(),
+			Measure:     watchedResourcesM,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Description: managedTriggersM.Description(),
+			Measure:     managedTriggersM,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{namespaceKey},
+		},
+		&view.View{
+			Description: notReadyTriggersM.Description(),
+			Measure:     notReadyTriggersM,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{namespaceKey},
+		},
+	); err != nil {
+		panic(err)
+	}
+}
+
+// StatsReporter reports the metrics of the autotrigger controllers.
+type StatsReporter interface {
+	// ReportReconcile reports the latency and outcome of reconciling an
+	// Addressable of the resource.
+	ReportReconcile(resource string, duration time.Duration, err error) error
+	// ReportRequeueDelay reports the delay the workqueue rate limiter of the
+	// controller of the resource put on an Addressable.
+	ReportRequeueDelay(resource string, delay time.Duration) error
+	// ReportWriteBudgetWait reports how long a write to the resource waited
+	// for the write budget.
+	ReportWriteBudgetWait(resource string, wait time.Duration) error
+	// ReportTriggerOperation reports a Trigger created, updated, deleted or adopted
+	// for an Addressable of the resource.
+	ReportTriggerOperation(resource, operation string) error
+	// ReportDryRunOperation reports a write skipped in dry-run mode while
+	// reconciling an object of the resource.
+	ReportDryRunOperation(resource, operation string) error
+	// ReportFilterParseFailure reports a filter annotation on an Addressable
+	// of the resource that could not be parsed.
+	ReportFilterParseFailure(resource string) error
+	// ReportWatchedResources reports the number of Addressable resources
+	// watched.
+	ReportWatchedResources(count int) error
+	// ReportTriggers reports the number of Triggers made by autotrigger in the
+	// namespace, and how many of them are not Ready.
+	ReportTriggers(namespace string, managed, notReady int) error
+}
+
+type reporter struct{}
+
+// NewStatsReporter creates a StatsReporter. The measurements go to the
+// exporter configured by the config-observability ConfigMap.
+func NewStatsReporter() StatsReporter {
+	return &reporter{}
+}
+
+func (r *reporter) ReportReconcile(resource string, duration time.Duration, err error) error {
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(resourceKey, resource),
+		tag.Insert(resultKey, result))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, reconcileLatencyM.M(float64(duration/time.Millisecond)))
+	return nil
+}
+
+func (r *reporter) ReportRequeueDelay(resource string, delay time.Duration) error {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(resourceKey, resource))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, requeueDelayM.M(float64(delay/time.Millisecond)))
+	return nil
+}
+
+func (r *reporter) ReportWriteBudgetWait(resource string, wait time.Duration) error {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(resourceKey, resource))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, writeBudgetWaitM.M(float64(wait/time.Millisecond)))
+	return nil
+}
+
+func (r *reporter) ReportTriggerOperation(resource, operation string) error {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(resourceKey, resource),
+		tag.Insert(operationKey, operation))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, triggerOperationsM.M(1))
+	return nil
+}
+
+func (r *reporter) ReportDryRunOperation(resource, operation string) error {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(resourceKey, resource),
+		tag.Insert(operationKey, operation))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, dryRunOperationsM.M(1))
+	return nil
+}
+
+func (r *reporter) ReportFilterParseFailure(resource string) error {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(resourceKey, resource))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, filterParseFailuresM.M(1))
+	return nil
+}
+
+func (r *reporter) ReportWatchedResources(count int) error {
+	metrics.Record(context.Background(), watchedResourcesM.M(int64(count)))
+	return nil
+}
+
+func (r *reporter) ReportTriggers(namespace string, managed, notReady int) error {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(namespaceKey, namespace))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, managedTriggersM.M(int64(managed)))
+	metrics.Record(ctx, notReadyTriggersM.M(int64(notReady)))
+	return nil
+}
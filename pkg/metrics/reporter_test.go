@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.opencensus.io/stats/view"
+)
+
+func TestReportReconcile(t *testing.T) {
+	r := NewStatsReporter()
+
+	if err := r.ReportReconcile("services.v1.serving.knative.dev", 10*time.Millisecond, nil); err != nil {
+		t.Fatalf("ReportReconcile() = %v", err)
+	}
+	if err := r.ReportReconcile("services.v1.serving.knative.dev", 20*time.Millisecond, errors.New("boom")); err != nil {
+		t.Fatalf("ReportReconcile() = %v", err)
+	}
+
+	rows, err := view.RetrieveData("addressable_reconcile_count")
+	if err != nil {
+		t.Fatalf("RetrieveData() = %v", err)
+	}
+	results := make(map[string]int64)
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key == resultKey {
+				results[tag.Value] = row.Data.(*view.CountData).Value
+			}
+		}
+	}
+	if results[resultSuccess] != 1 || results[resultError] != 1 {
+		t.Errorf("addressable_reconcile_count = %v, wanted one success and one error", results)
+	}
+}
+
+func TestReportTriggers(t *testing.T) {
+	r := NewStatsReporter()
+
+	if err := r.ReportTriggers("default", 3, 1); err != nil {
+		t.Fatalf("ReportTriggers() = %v", err)
+	}
+
+	for name, want := range map[string]float64{
+		"managed_triggers":   3,
+		"not_ready_triggers": 1,
+	} {
+		rows, err := view.RetrieveData(name)
+		if err != nil {
+			t.Fatalf("RetrieveData(%q) = %v", name, err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("%s has %d rows, wanted 1", name, len(rows))
+		}
+		if got := rows[0].Data.(*view.LastValueData).Value; got != want {
+			t.Errorf("%s = %v, wanted %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+)
+
+// writeGroups are the API groups whose writes take from the Budget.
+var writeGroups = map[string]bool{
+	"eventing.knative.dev":  true,
+	"messaging.knative.dev": true,
+}
+
+// Budget limits the writes to the eventing APIs made through the clients of a
+// rest.Config it wraps, so that a burst of work in many controllers does not
+// use up the client for everyone, or swamp the API server.
+type Budget struct {
+	stats metrics.StatsReporter
+
+	m       sync.RWMutex
+	qps     float64
+	burst   int
+	limiter *rate.Limiter
+}
+
+// NewBudget returns a Budget with no limit until SetLimits is called.
+func NewBudget(stats metrics.StatsReporter) *Budget {
+	return &Budget{
+		limiter: rate.NewLimiter(rate.Inf, 0),
+		stats:   stats,
+	}
+}
+
+// SetLimits changes the rate and burst of the Budget. A QPS of zero lifts the
+// limit.
+func (b *Budget) SetLimits(qps float64, burst int) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	// The bucket starts full again, so only replace it when it changes.
+	if b.qps == qps && b.burst == burst {
+		return
+	}
+	b.qps, b.burst = qps, burst
+	if qps <= 0 {
+		b.limiter = rate.NewLimiter(rate.Inf, 0)
+	} else {
+		b.limiter = rate.NewLimiter(rate.Limit(qps), burst)
+	}
+}
+
+// wait blocks until the Budget allows a write, or the context is done.
+func (b *Budget) wait(ctx context.Context) error {
+	b.m.RLock()
+	limiter := b.limiter
+	b.m.RUnlock()
+	return limiter.Wait(ctx)
+}
+
+// Wrap is a transport.WrapperFunc that makes the writes to the eventing APIs
+// wait for the Budget.
+func (b *Budget) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return &budgetRoundTripper{budget: b, next: rt}
+}
+
+type budgetRoundTripper struct {
+	budget *Budget
+	next   http.RoundTripper
+}
+
+func (rt *budgetRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if resource, ok := eventingWrite(req); ok {
+		start := time.Now()
+		if err := rt.budget.wait(req.Context()); err != nil {
+			return nil, err
+		}
+		rt.budget.stats.ReportWriteBudgetWait(resource, time.Since(start))
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// eventingWrite returns the resource written by the request, as
+// "resource.group", if it is a write to one of the eventing API groups.
+func eventingWrite(req *http.Request) (string, bool) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return "", false
+	}
+	// /apis/<group>/<version>[/namespaces/<namespace>]/<resource>[/...]
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "apis" || !writeGroups[parts[1]] {
+		return "", false
+	}
+	resource := parts[3]
+	if resource == "namespaces" && len(parts) >= 6 {
+		resource = parts[5]
+	}
+	return resource + "." + parts[1], true
+}
+
+type budgetKey struct{}
+
+// WithBudget attaches the Budget to the context, for the controllers to
+// configure.
+func WithBudget(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// BudgetFromContext returns the Budget attached to the context, or nil.
+func BudgetFromContext(ctx context.Context) *Budget {
+	b, _ := ctx.Value(budgetKey{}).(*Budget)
+	return b
+}
+
+// NewWorkQueueRateLimiter returns a rate limiter for the workqueue of the
+// controller of the resource, built like workqueue.DefaultControllerRateLimiter
+// with retries backing off from baseDelay to maxDelay and a bucket of qps and
+// burst, that reports the delays it asks for. A qps of zero lifts the limit.
+func NewWorkQueueRateLimiter(baseDelay, maxDelay time.Duration, qps float64, burst int, resource string, stats metrics.StatsReporter) workqueue.RateLimiter {
+	limit := rate.Inf
+	if qps > 0 {
+		limit = rate.Limit(qps)
+	}
+	return &reportingRateLimiter{
+		RateLimiter: workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(limit, burst)},
+		),
+		resource: resource,
+		stats:    stats,
+	}
+}
+
+type reportingRateLimiter struct {
+	workqueue.RateLimiter
+	resource string
+	stats    metrics.StatsReporter
+}
+
+func (r *reportingRateLimiter) When(item interface{}) time.Duration {
+	delay := r.RateLimiter.When(item)
+	r.stats.ReportRequeueDelay(r.resource, delay)
+	return delay
+}
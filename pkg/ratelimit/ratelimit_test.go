@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+)
+
+func TestEventingWrite(t *testing.T) {
+	tests := map[string]struct {
+		method string
+		path   string
+		want   string
+	}{
+		"create trigger": {
+			method: http.MethodPost,
+			path:   "/apis/eventing.knative.dev/v1alpha1/namespaces/default/triggers",
+			want:   "triggers.eventing.knative.dev",
+		},
+		"update sequence": {
+			method: http.MethodPut,
+			path:   "/apis/messaging.knative.dev/v1alpha1/namespaces/default/sequences/seq",
+			want:   "sequences.messaging.knative.dev",
+		},
+		"get trigger": {
+			method: http.MethodGet,
+			path:   "/apis/eventing.knative.dev/v1alpha1/namespaces/default/triggers/foo",
+		},
+		"create event": {
+			method: http.MethodPost,
+			path:   "/api/v1/namespaces/default/events",
+		},
+		"update lease": {
+			method: http.MethodPut,
+			path:   "/apis/coordination.k8s.io/v1/namespaces/default/leases/autotrigger-controller",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, "https://kubernetes"+tc.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := eventingWrite(req)
+			if ok != (tc.want != "") || got != tc.want {
+				t.Errorf("eventingWrite() = %q, %v, wanted %q", got, ok, tc.want)
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(metrics.NewStatsReporter())
+	b.SetLimits(1, 1)
+
+	calls := 0
+	rt := b.Wrap(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	write := func() error {
+		req, _ := http.NewRequest(http.MethodPost, "https://kubernetes/apis/eventing.knative.dev/v1alpha1/namespaces/default/triggers", nil)
+		_, err := rt.RoundTrip(req.WithContext(ctx))
+		return err
+	}
+	read := func() error {
+		req, _ := http.NewRequest(http.MethodGet, "https://kubernetes/apis/eventing.knative.dev/v1alpha1/namespaces/default/triggers", nil)
+		_, err := rt.RoundTrip(req.WithContext(ctx))
+		return err
+	}
+
+	if err := write(); err != nil {
+		t.Fatalf("first write = %v", err)
+	}
+	if err := read(); err != nil {
+		t.Fatalf("read = %v", err)
+	}
+	// The bucket is empty, and is not refilled before the context is done.
+	if err := write(); err == nil {
+		t.Error("second write = nil, wanted it to be throttled")
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, wanted 2", calls)
+	}
+
+	b.SetLimits(0, 0)
+	if err := write(); err != nil {
+		t.Errorf("write without limit = %v", err)
+	}
+}
+
+func TestWorkQueueRateLimiter(t *testing.T) {
+	rl := NewWorkQueueRateLimiter(time.Millisecond, 4*time.Millisecond, 0, 0, "services.v1.serving.knative.dev", metrics.NewStatsReporter())
+
+	var got []time.Duration
+	for i := 0; i < 4; i++ {
+		got = append(got, rl.When("default/foo"))
+	}
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("When() #%d = %v, wanted %v", i, got[i], want[i])
+		}
+	}
+	rl.Forget("default/foo")
+	if d := rl.When("default/foo"); d != time.Millisecond {
+		t.Errorf("When() after Forget() = %v, wanted %v", d, time.Millisecond)
+	}
+}
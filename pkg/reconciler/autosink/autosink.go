@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autosink
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autosink/resources"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes"
+	"github.com/n3wscott/autotrigger/pkg/scope"
+)
+
+// Reconciler implements controller.Reconciler for Source resources.
+type Reconciler struct {
+	// Source
+	sourceLister cache.GenericLister
+
+	dynamicClientSet dynamic.Interface
+	gvr              schema.GroupVersionResource
+
+	// scope is the set of namespaces the controller acts in.
+	scope           *scope.Scope
+	namespaceLister corev1listers.NamespaceLister
+
+	eventTypes *eventtypes.Reconciler
+
+	configStore *config.Store
+	// dryRun reports the sinks that are not set in dry-run mode.
+	dryRun dryrun.Reporter
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ controller.Reconciler = (*Reconciler)(nil)
+
+// Reconcile
+func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorf("invalid resource key: %s", key)
+		return nil
+	}
+
+	if c.configStore != nil {
+		ctx = c.configStore.ToContext(ctx)
+	}
+
+	if ok, err := c.scope.Allows(c.namespaceLister, namespace); err != nil {
+		return err
+	} else if !ok {
+		logger.Debugf("%s is out of the scope of the controller", key)
+		return nil
+	}
+
+	// Get the Source resource with this namespace/name
+	runtimeobj, err := c.sourceLister.ByNamespace(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		// The resource may no longer exist, in which case we stop processing.
+		logger.Errorf("source %q in work queue no longer exists", key)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	original, ok := runtimeobj.(*duckv1.Source)
+	if !ok {
+		logger.Errorf("runtime object is not convertible to source type, key=%q", key)
+		return nil
+	}
+
+	// Don't modify the informers copy
+	return c.reconcile(ctx, original.DeepCopy())
+}
+
+func (c *Reconciler) reconcile(ctx context.Context, source *duckv1.Source) error {
+	logger := logging.FromContext(ctx)
+
+	if source.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	if resources.AutoSinkEnabled(source) {
+		if err := c.reconcileSink(ctx, source); err != nil {
+			return err
+		}
+	}
+
+	// Sources may declare the event types they emit on the Broker they send to.
+	if err := c.eventTypes.Reconcile(ctx, source, resources.Broker(source)); err != nil {
+		logger.Errorw(fmt.Sprintf("failed to reconcile EventTypes for %q", source.Name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *Reconciler) reconcileSink(ctx context.Context, source *duckv1.Source) error {
+	logger := logging.FromContext(ctx)
+
+	sink := resources.MakeSink(source)
+	if equality.Semantic.DeepEqual(source.Spec.Sink, *sink) {
+		return nil
+	}
+
+	desired := source.DeepCopy()
+	desired.Spec.Sink = *sink
+
+	patch, err := duck.CreateMergePatch(source, desired)
+	if err != nil {
+		return err
+	}
+
+	if dryrun.Enabled(ctx) {
+		c.dryRun.Report(ctx, source, metrics.OperationPatch, source.Kind, source.Namespace, source.Name)
+		return nil
+	}
+
+	if _, err := c.dynamicClientSet.Resource(c.gvr).Namespace(source.Namespace).Patch(source.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		logger.Errorw(fmt.Sprintf("failed to set sink for Source %q", source.Name), zap.Error(err))
+		return err
+	}
+	logger.Infof("set sink for Source %q to Broker %q", source.Name, sink.Ref.Name)
+	return nil
+}
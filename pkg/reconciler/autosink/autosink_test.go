@@ -0,0 +1,244 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autosink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/controller"
+
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes/resources"
+	. "github.com/n3wscott/autotrigger/pkg/reconciler/testing"
+)
+
+const (
+	testNS     = "test-namespace"
+	sourceName = "test-source"
+)
+
+var pingSourcesGVR = schema.GroupVersionResource{Group: "sources.eventing.knative.dev", Version: "v1alpha1", Resource: "pingsources"}
+
+type sourceOption func(*duckv1.Source)
+
+func newSource(opts ...sourceOption) *duckv1.Source {
+	s := &duckv1.Source{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "sources.eventing.knative.dev/v1alpha1",
+			Kind:       "PingSource",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      sourceName,
+			UID:       "test-source-uid",
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func withAutoSink(s *duckv1.Source) {
+	s.Labels = map[string]string{"eventing.knative.dev/autosink": "true"}
+}
+
+func withBroker(name string) sourceOption {
+	return func(s *duckv1.Source) {
+		s.Annotations = map[string]string{"sink.eventing.knative.dev/broker": name}
+	}
+}
+
+func withSink(apiVersion, kind, name string) sourceOption {
+	return func(s *duckv1.Source) {
+		s.Spec.Sink = v1alpha1.Destination{
+			Ref: &corev1.ObjectReference{
+				APIVersion: apiVersion,
+				Kind:       kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func withProduces(produces string) sourceOption {
+	return func(s *duckv1.Source) {
+		s.Annotations = map[string]string{"eventing.knative.dev/produces": produces}
+	}
+}
+
+func withDeletionTimestamp(s *duckv1.Source) {
+	now := metav1.Now()
+	s.DeletionTimestamp = &now
+}
+
+// sinkPatch is the merge patch pointing the Source at the Broker.
+func sinkPatch(broker string) []Action {
+	return []Action{{
+		Resource:  "pingsources",
+		Namespace: testNS,
+		Name:      sourceName,
+		Patch:     `{"spec":{"sink":{"ref":{"apiVersion":"eventing.knative.dev/v1alpha1","kind":"Broker","name":"` + broker + `"}}}}`,
+	}}
+}
+
+func dryRun(t *testing.T) context.Context {
+	cfg, err := config.NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.ConfigName},
+		Data:       map[string]string{"dry-run": "true"},
+	})
+	if err != nil {
+		t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+	}
+	return config.ToContext(context.Background(), &config.Config{AutoTrigger: cfg})
+}
+
+func TestReconcile(t *testing.T) {
+	key := testNS + "/" + sourceName
+
+	producer := newSource(withProduces(`[{"type":"dev.example.ping","source":"/ping"}]`))
+	eventTypes, err := resources.MakeEventTypes(producer, "default")
+	if err != nil {
+		t.Fatalf("MakeEventTypes() = %v", err)
+	}
+	alreadyExists := func(action Action) error {
+		if action.Verb != VerbCreate || action.Resource != "eventtypes" {
+			return nil
+		}
+		return apierrs.NewAlreadyExists(schema.GroupResource{Group: "eventing.knative.dev", Resource: "eventtypes"}, eventTypes[0].Name)
+	}
+
+	dryRunOperations := func(want map[string]int) func(*testing.T, *TableRow, Fakes) {
+		return func(t *testing.T, _ *TableRow, f Fakes) {
+			if diff := cmp.Diff(want, f.Stats.DryRunOperations); diff != "" {
+				t.Errorf("DryRunOperations (-want, +got) = %s", diff)
+			}
+		}
+	}
+
+	table := TableTest{{
+		Name: "bad key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "source not found",
+		Key:  key,
+	}, {
+		Name:    "not labeled",
+		Key:     key,
+		Objects: []runtime.Object{newSource()},
+	}, {
+		Name:    "being deleted",
+		Key:     key,
+		Objects: []runtime.Object{newSource(withAutoSink, withDeletionTimestamp)},
+	}, {
+		Name:        "sink missing, default broker",
+		Key:         key,
+		Objects:     []runtime.Object{newSource(withAutoSink)},
+		WantPatches: sinkPatch("default"),
+	}, {
+		Name:        "sink missing, annotated broker",
+		Key:         key,
+		Objects:     []runtime.Object{newSource(withAutoSink, withBroker("shared"))},
+		WantPatches: sinkPatch("shared"),
+	}, {
+		Name:        "sink missing, empty broker annotation",
+		Key:         key,
+		Objects:     []runtime.Object{newSource(withAutoSink, withBroker(""))},
+		WantPatches: sinkPatch("default"),
+	}, {
+		Name:    "sink already set",
+		Key:     key,
+		Objects: []runtime.Object{newSource(withAutoSink, withBroker("shared"), withSink("eventing.knative.dev/v1alpha1", "Broker", "shared"))},
+	}, {
+		Name:        "sink pointing elsewhere",
+		Key:         key,
+		Objects:     []runtime.Object{newSource(withAutoSink, withSink("serving.knative.dev/v1", "Service", "display"))},
+		WantPatches: sinkPatch("default"),
+	}, {
+		Name:         "patch fails",
+		Key:          key,
+		Objects:      []runtime.Object{newSource(withAutoSink)},
+		WithReactors: []Reactor{InduceFailure(VerbPatch, "pingsources")},
+		WantErr:      true,
+		WantPatches:  sinkPatch("default"),
+	}, {
+		Name:        "create event types",
+		Key:         key,
+		Objects:     []runtime.Object{producer},
+		WantCreates: []runtime.Object{eventTypes[0]},
+	}, {
+		Name:         "event type already exists",
+		Key:          key,
+		Objects:      []runtime.Object{producer},
+		WithReactors: []Reactor{alreadyExists},
+		WantCreates:  []runtime.Object{eventTypes[0]},
+	}, {
+		Name:    "event type up to date",
+		Key:     key,
+		Objects: []runtime.Object{producer, eventTypes[0]},
+	}, {
+		Name:    "dry run, sink and event types created",
+		Ctx:     dryRun(t),
+		Key:     key,
+		Objects: []runtime.Object{newSource(withAutoSink, withProduces(`[{"type":"dev.example.ping","source":"/ping"}]`))},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would %s %s %s/%s", "patch", "PingSource", testNS, sourceName),
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would %s %s %s/%s", "create", "EventType", testNS, eventTypes[0].Name),
+		},
+		PostConditions: []func(*testing.T, *TableRow, Fakes){dryRunOperations(map[string]int{"patch": 1, "create": 1})},
+	}, {
+		Name:    "dry run, event types deleted",
+		Ctx:     dryRun(t),
+		Key:     key,
+		Objects: []runtime.Object{newSource(), eventTypes[0]},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would %s %s %s/%s", "delete", "EventType", testNS, eventTypes[0].Name),
+		},
+		PostConditions: []func(*testing.T, *TableRow, Fakes){dryRunOperations(map[string]int{"delete": 1})},
+	}}
+
+	table.Test(t, func(t *testing.T, r *TableRow, f Fakes) controller.Reconciler {
+		dryRun := dryrun.Reporter{
+			Resource: pingSourcesGVR.String(),
+			Stats:    f.Stats,
+			Recorder: f.Recorder,
+		}
+		return &Reconciler{
+			sourceLister:     f.Listers.GetSourceLister(pingSourcesGVR),
+			dynamicClientSet: f.Dynamic(),
+			gvr:              pingSourcesGVR,
+			namespaceLister:  f.Listers.GetNamespaceLister(),
+			eventTypes: &eventtypes.Reconciler{
+				EventingClientSet: f.Client,
+				EventTypeLister:   f.Listers.GetEventTypeLister(),
+				DryRun:            dryRun,
+			},
+			dryRun: dryRun,
+		}
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autosink
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/injection/clients/dynamicclient"
+	"knative.dev/pkg/logging"
+
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	eventtypeinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/eventtype"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes"
+	"github.com/n3wscott/autotrigger/pkg/scope"
+)
+
+// NewControllerConstructor returns a constructor for a controller that points
+// labeled Sources of the given resource, of kind gk, at a Broker.
+func NewControllerConstructor(name string, gvr schema.GroupVersionResource, gk schema.GroupKind) injection.ControllerConstructor {
+	return func(
+		ctx context.Context,
+		cmw configmap.Watcher,
+	) *controller.Impl {
+		logger := logging.FromContext(ctx)
+
+		eventTypeInformer := eventtypeinformer.Get(ctx)
+
+		sourceinformer := &reconciler.SelectedInformerFactory{
+			Client:       dynamicclient.Get(ctx),
+			Type:         &duckv1.Source{},
+			Namespace:    injection.GetNamespaceScope(ctx),
+			ResyncPeriod: 10 * time.Hour,
+			StopChannel:  ctx.Done(),
+		}
+
+		sourceInformer, sourceLister, err := sourceinformer.Get(gvr)
+		if err != nil {
+			panic(err)
+		}
+
+		// The configuration is read for dry-run mode.
+		configStore := config.NewStore(logger.Named("config-store"))
+		configStore.WatchConfigs(cmw)
+		dryRun := dryrun.Reporter{
+			Resource: gvr.String(),
+			Stats:    metrics.NewStatsReporter(),
+			Recorder: controller.GetEventRecorder(ctx),
+		}
+
+		c := &Reconciler{
+			dynamicClientSet: dynamicclient.Get(ctx),
+			sourceLister:     sourceLister,
+			gvr:              gvr,
+			scope:            scope.FromContext(ctx),
+			namespaceLister:  namespace.Get(ctx).Lister(),
+			eventTypes: &eventtypes.Reconciler{
+				EventingClientSet: eventingclient.Get(ctx),
+				EventTypeLister:   eventTypeInformer.Lister(),
+				DryRun:            dryRun,
+			},
+			configStore: configStore,
+			dryRun:      dryRun,
+		}
+		impl := controller.NewImpl(c, logger, name)
+
+		logger.Infof("Setting up event handlers for %s", name)
+
+		sourceInformer.AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+		// Look again at the Source when one of its EventTypes is changed or
+		// deleted.
+		eventTypeInformer.Informer().AddEventHandler(eventtypes.Handler(impl, gk))
+
+		return impl
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis/v1alpha1"
+)
+
+const (
+	autoSinkLabel    = "eventing.knative.dev/autosink"
+	brokerAnnotation = "sink.eventing.knative.dev/broker"
+
+	defaultBroker = "default"
+)
+
+// AutoSinkEnabled returns true if the Source has opted in to having its sink
+// managed by autosink.
+func AutoSinkEnabled(s *duckv1.Source) bool {
+	if enabled, ok := s.Labels[autoSinkLabel]; ok {
+		if strings.EqualFold(enabled, "true") {
+			return true
+		}
+	}
+	return false
+}
+
+// Broker returns the name of the Broker the Source should send to, either
+// from the broker annotation or the namespace default Broker.
+func Broker(s *duckv1.Source) string {
+	if b, ok := s.Annotations[brokerAnnotation]; ok && b != "" {
+		return b
+	}
+	return defaultBroker
+}
+
+// MakeSink creates the sink Destination pointing a Source at its Broker.
+func MakeSink(s *duckv1.Source) *v1alpha1.Destination {
+	return &v1alpha1.Destination{
+		Ref: &corev1.ObjectReference{
+			APIVersion: eventingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Broker",
+			Name:       Broker(s),
+		},
+	}
+}
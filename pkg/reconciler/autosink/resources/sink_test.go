@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis/v1alpha1"
+)
+
+func TestMakeSink(t *testing.T) {
+	tests := []struct {
+		name   string
+		source *duckv1.Source
+		want   *v1alpha1.Destination
+	}{{
+		name: "default broker",
+		source: &duckv1.Source{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				Labels:    map[string]string{autoSinkLabel: "true"},
+			},
+		},
+		want: &v1alpha1.Destination{
+			Ref: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Broker",
+				Name:       "default",
+			},
+		},
+	}, {
+		name: "annotated broker",
+		source: &duckv1.Source{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "default",
+				Labels:      map[string]string{autoSinkLabel: "true"},
+				Annotations: map[string]string{brokerAnnotation: "other"},
+			},
+		},
+		want: &v1alpha1.Destination{
+			Ref: &corev1.ObjectReference{
+				APIVersion: "eventing.knative.dev/v1alpha1",
+				Kind:       "Broker",
+				Name:       "other",
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := MakeSink(test.source)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("MakeSink (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -20,26 +20,45 @@ import (
 	"context"
 	"fmt"
 	"github.com/n3wscott/autotrigger/pkg/reconciler"
+	"time"
 
 	"go.uber.org/zap"
-	"k8s.io/apimachinery/pkg/api/equality"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
 	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
 	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	atapi "github.com/n3wscott/autotrigger/pkg/autotrigger"
+	autotriggerlisters "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
 	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline"
+	"github.com/n3wscott/autotrigger/pkg/scope"
+	"github.com/n3wscott/autotrigger/pkg/tracing"
 )
 
+var triggersGVR = eventingv1alpha1.SchemeGroupVersion.WithResource("triggers")
+
 // Reconciler implements controller.Reconciler for Addressable resources.
 type Reconciler struct {
 	// Addressable
@@ -50,18 +69,50 @@ type Reconciler struct {
 	// Eventing
 	eventingClientSet eventingclientset.Interface
 	triggerLister     eventinglisters.TriggerLister
+	triggerIndexer    cache.Indexer
 	gvr               schema.GroupVersionResource
+	gk                schema.GroupKind
+
+	eventTypes *eventtypes.Reconciler
+	pipelines  *pipeline.Membership
+
+	profileLister autotriggerlisters.AutoTriggerProfileLister
+	bindingLister autotriggerlisters.AutoTriggerBindingLister
+
+	// Policy
+	policyLister    autotriggerlisters.AutoTriggerPolicyLister
+	namespaceLister corev1listers.NamespaceLister
+	recorder        record.EventRecorder
+
+	// scope is the set of namespaces the controller acts in.
+	scope *scope.Scope
+
+	configStore *config.Store
+	stats       metrics.StatsReporter
+
+	// addressableClient looks up the Addressables missing from the lister
+	// when it only holds the labeled ones, and is nil otherwise.
+	addressableClient dynamic.NamespaceableResourceInterface
 }
 
 // Check that our Reconciler implements controller.Reconciler
 var _ controller.Reconciler = (*Reconciler)(nil)
 
 // Reconcile
-func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
+func (c *Reconciler) Reconcile(ctx context.Context, key string) (err error) {
 	logger := logging.FromContext(ctx)
 
+	start := time.Now()
+	defer func() {
+		c.stats.ReportReconcile(c.gvr.String(), time.Since(start), err)
+	}()
+
 	logger.Infof("Reconcile %s", c.gvr.String())
 
+	if c.configStore != nil {
+		ctx = c.configStore.ToContext(ctx)
+	}
+
 	// Convert the namespace/name string into a distinct namespace and name
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
@@ -69,23 +120,43 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if ok, err := c.scope.Allows(c.namespaceLister, namespace); err != nil {
+		return err
+	} else if !ok {
+		logger.Debugf("%s is out of the scope of the controller", key)
+		return nil
+	}
+
+	ctx, span := tracing.StartSpan(ctx, "Reconcile", c.gvr, namespace, name)
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// Get the Addressable resource with this namespace/name
+	_, listerSpan := tracing.StartSpan(ctx, "GetAddressable", c.gvr, namespace, name)
 	runtimeobj, err := c.addressableLister.ByNamespace(namespace).Get(name)
-
-	var ok bool
-	var original *duckv1.AddressableType
-	if original, ok = runtimeobj.(*duckv1.AddressableType); !ok {
-		logger.Errorf("runtime object is not convertible to addressable type, key=%q", key)
-		return nil
+	if apierrs.IsNotFound(err) && c.addressableClient != nil {
+		// Only the labeled Addressables are cached. The ones that lost the
+		// label, or are only bound, are looked up so that their Triggers
+		// are reconciled rather than treated as orphans.
+		runtimeobj, err = c.getAddressable(namespace, name)
 	}
+	tracing.EndSpan(listerSpan, ignoreNotFound(err))
 
 	if apierrs.IsNotFound(err) {
 		// The resource may no longer exist, in which case we stop processing.
-		logger.Errorf("addressable %q in work queue no longer exists", key)
-		return nil
+		// Triggers in its own namespace are garbage collected, but the ones in
+		// other namespaces have to be cleaned up here.
+		logger.Infof("addressable %q in work queue no longer exists", key)
+		return c.deleteRemoteTriggers(ctx, namespace, name)
 	} else if err != nil {
 		return err
-	} else if !resources.AutoTriggerEnabled(original) {
+	}
+
+	var ok bool
+	var original *duckv1.AddressableType
+	if original, ok = runtimeobj.(*duckv1.AddressableType); !ok {
+		logger.Errorf("runtime object is not convertible to addressable type, key=%q", key)
 		return nil
 	}
 
@@ -94,6 +165,19 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 	return c.reconcile(ctx, original.DeepCopy())
 }
 
+// getAddressable gets the Addressable from the API server.
+func (c *Reconciler) getAddressable(namespace, name string) (*duckv1.AddressableType, error) {
+	u, err := c.addressableClient.Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addressable := &duckv1.AddressableType{}
+	if err := duck.FromUnstructured(u, addressable); err != nil {
+		return nil, err
+	}
+	return addressable, nil
+}
+
 func (c *Reconciler) reconcile(ctx context.Context, addressable *duckv1.AddressableType) error {
 	logger := logging.FromContext(ctx)
 
@@ -110,14 +194,185 @@ func (c *Reconciler) reconcile(ctx context.Context, addressable *duckv1.Addressa
 		}
 	}
 
-	triggers, err := c.triggerLister.Triggers(addressable.Namespace).List(labels.SelectorFromSet(resources.MakeLabels(addressable)))
+	optedIn, err := c.namespaceOptedIn(ctx, addressable.Namespace)
+	if err != nil {
+		return err
+	}
+
+	bindings, err := c.boundBy(ctx, addressable)
+	if err != nil {
+		return err
+	}
+
+	defaults, err := c.namespaceDefaults(ctx, addressable)
+	if err != nil {
+		return err
+	}
+
+	// Everything made for the Addressables of a namespace that has not opted
+	// in is cleaned up.
+	enabled := optedIn && (resources.AutoTriggerEnabled(addressable) || len(bindings) > 0 || len(defaults) > 0)
+	if enabled {
+		// The bindings and the namespace defaults add to the filters the
+		// Addressable declares itself.
+		filters := make([]atapi.Filter, 0, len(defaults))
+		for _, binding := range bindings {
+			filters = append(filters, resources.BindingFilters(binding)...)
+		}
+		filters = append(filters, defaults...)
+		if err := c.reconcileAutoTriggers(ctx, addressable, filters); err != nil {
+			return err
+		}
+	} else if err := c.deleteAutoTriggers(ctx, addressable); err != nil {
+		return err
+	}
+
+	if err := c.pipelines.Reconcile(ctx, addressable, enabled); err != nil {
+		logger.Errorw(fmt.Sprintf("failed to reconcile pipelines for %q", addressable.Name), zap.Error(err))
+		return err
+	}
+
+	if !optedIn {
+		if err := c.eventTypes.Delete(ctx, addressable); err != nil {
+			logger.Errorw(fmt.Sprintf("failed to delete EventTypes for %q", addressable.Name), zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
+	// Producers may declare the event types they emit, independent of autotrigger.
+	if err := c.eventTypes.Reconcile(ctx, addressable, resources.DefaultBroker); err != nil {
+		logger.Errorw(fmt.Sprintf("failed to reconcile EventTypes for %q", addressable.Name), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// namespaceOptedIn reports whether the controller may act on the Addressables
+// of the namespace: always, unless the namespace opt-in is configured, in
+// which case the namespace needs the NamespaceEnabledLabel.
+func (c *Reconciler) namespaceOptedIn(ctx context.Context, namespace string) (bool, error) {
+	if !config.FromContextOrDefaults(ctx).AutoTrigger.NamespaceOptIn {
+		return true, nil
+	}
+	_, span := tracing.StartSpan(ctx, "GetNamespace", c.gvr, namespace, namespace)
+	ns, err := c.namespaceLister.Get(namespace)
+	tracing.EndSpan(span, ignoreNotFound(err))
+	if apierrs.IsNotFound(err) {
+		// The namespace is going away, along with its Addressables.
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return resources.NamespaceEnabled(ns), nil
+}
+
+// namespaceDefaults returns the entries of the default filter of the namespace
+// of the Addressable that apply to it. A default filter that cannot be parsed
+// is reported as an event and left out, rather than holding back the Triggers
+// of every Addressable in the namespace.
+func (c *Reconciler) namespaceDefaults(ctx context.Context, addressable *duckv1.AddressableType) ([]atapi.Filter, error) {
+	logger := logging.FromContext(ctx)
+
+	_, span := tracing.StartSpan(ctx, "GetNamespace", c.gvr, addressable.Namespace, addressable.Name)
+	ns, err := c.namespaceLister.Get(addressable.Namespace)
+	tracing.EndSpan(span, ignoreNotFound(err))
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	defaults, err := resources.NamespaceDefaultFilters(ns, c.gk, addressable)
+	if err != nil {
+		c.stats.ReportFilterParseFailure(c.gvr.String())
+		logger.Infof("%s/%s: %v", addressable.Namespace, addressable.Name, err)
+		if c.recorder != nil {
+			c.recorder.Event(addressable, corev1.EventTypeWarning, "DefaultFilterInvalid", err.Error())
+		}
+		return nil, nil
+	}
+	return defaults, nil
+}
+
+// boundBy returns the AutoTriggerBindings that select the Addressable. A
+// binding with a selector that cannot be parsed is reported as an event and
+// left out, rather than holding back every Addressable in its namespace.
+func (c *Reconciler) boundBy(ctx context.Context, addressable *duckv1.AddressableType) ([]*autotriggerv1alpha1.AutoTriggerBinding, error) {
+	logger := logging.FromContext(ctx)
+
+	_, span := tracing.StartSpan(ctx, "ListAutoTriggerBindings", c.gvr, addressable.Namespace, addressable.Name)
+	bindings, err := c.bindingLister.AutoTriggerBindings(addressable.Namespace).List(labels.Everything())
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	bound := make([]*autotriggerv1alpha1.AutoTriggerBinding, 0)
+	for _, binding := range bindings {
+		if ok, err := resources.BindingMatches(binding, addressable); err != nil {
+			logger.Infof("AutoTriggerBinding %s/%s: invalid selector: %v", binding.Namespace, binding.Name, err)
+			if c.recorder != nil {
+				c.recorder.Eventf(binding, corev1.EventTypeWarning, "InvalidSelector", "Invalid selector: %v", err)
+			}
+		} else if ok {
+			bound = append(bound, binding)
+		}
+	}
+	return bound, nil
+}
+
+// existingTriggers lists the Triggers created for the Addressable, in its own
+// namespace and in others.
+func (c *Reconciler) existingTriggers(ctx context.Context, addressable *duckv1.AddressableType) ([]*eventingv1alpha1.Trigger, error) {
+	_, span := tracing.StartSpan(ctx, "ListTriggers", c.gvr, addressable.Namespace, addressable.Name)
+	objs, err := c.triggerIndexer.ByIndex(resources.OwnerUIDIndex, string(addressable.UID))
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	triggers := make([]*eventingv1alpha1.Trigger, 0, len(objs))
+	for _, obj := range objs {
+		if trigger, ok := obj.(*eventingv1alpha1.Trigger); ok {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers, nil
+}
+
+// deleteAutoTriggers deletes the Triggers left behind when an Addressable is
+// no longer labeled or bound. It runs for every Addressable that is not, so
+// it only deletes the Triggers that carry the managed-by label.
+func (c *Reconciler) deleteAutoTriggers(ctx context.Context, addressable *duckv1.AddressableType) error {
+	logger := logging.FromContext(ctx)
+
+	triggers, err := c.existingTriggers(ctx, addressable)
+	if err != nil {
+		return err
+	}
+	for _, trigger := range triggers {
+		if !resources.Managed(trigger) {
+			logger.Debugf("leaving Trigger %s/%s alone, it was not made by autotrigger", trigger.Namespace, trigger.Name)
+			continue
+		}
+		err := c.deleteTrigger(ctx, trigger)
+		if err != nil && !apierrs.IsNotFound(err) {
+			logger.Errorf("failed to delete Trigger %s/%s: %v", trigger.Namespace, trigger.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Reconciler) reconcileAutoTriggers(ctx context.Context, addressable *duckv1.AddressableType, filters []atapi.Filter) error {
+	logger := logging.FromContext(ctx)
 
-	triggers = filterTriggers(addressable, triggers)
+	triggers, err := c.existingTriggers(ctx, addressable)
 
 	// TODO: the trigger should only be made on the top most labeled addressable resource in the owner chain.
 
 	if errors.IsNotFound(err) || len(triggers) == 0 { // TODO: might not get an IsNotFound error for list.
-		triggers, err = c.createTriggers(ctx, addressable)
+		triggers, err = c.createTriggers(ctx, addressable, filters)
 		if err != nil {
 			logger.Errorf("failed to create Triggers for Service %q: %v", addressable.Name, err)
 			return err
@@ -125,7 +380,7 @@ func (c *Reconciler) reconcile(ctx context.Context, addressable *duckv1.Addressa
 	} else if err != nil {
 		logger.Errorw(fmt.Sprintf("failed to Get Triggers for Service %q", addressable.Name), zap.Error(err))
 		return err
-	} else if triggers, err = c.reconcileTriggers(ctx, addressable, triggers); err != nil {
+	} else if triggers, err = c.reconcileTriggers(ctx, addressable, triggers, filters); err != nil {
 		logger.Errorw(fmt.Sprintf("failed to reconcile Triggers for Service %q", addressable.Name), zap.Error(err))
 		return err
 	}
@@ -133,27 +388,42 @@ func (c *Reconciler) reconcile(ctx context.Context, addressable *duckv1.Addressa
 	return nil
 }
 
-func filterTriggers(addressable *duckv1.AddressableType, triggers []*eventingv1alpha1.Trigger) []*eventingv1alpha1.Trigger {
-	filteredTriggers := []*eventingv1alpha1.Trigger(nil)
+// deleteRemoteTriggers deletes the Triggers in other namespaces that were
+// created for the named Addressable.
+func (c *Reconciler) deleteRemoteTriggers(ctx context.Context, namespace, name string) error {
+	logger := logging.FromContext(ctx)
+
+	_, span := tracing.StartSpan(ctx, "ListTriggers", c.gvr, namespace, name)
+	triggers, err := c.triggerLister.List(labels.SelectorFromSet(resources.MakeOwnerSelector(namespace, name, c.gk)))
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return err
+	}
 	for _, trigger := range triggers {
-		if metav1.IsControlledBy(trigger, addressable) {
-			filteredTriggers = append(filteredTriggers, trigger)
+		if trigger.Namespace == namespace {
+			continue
+		}
+		err := c.deleteTrigger(ctx, trigger)
+		if err != nil && !apierrs.IsNotFound(err) {
+			logger.Errorf("failed to delete Trigger %s/%s: %v", trigger.Namespace, trigger.Name, err)
+			return err
 		}
 	}
-	return filteredTriggers
+	return nil
 }
 
-func (c *Reconciler) createTriggers(ctx context.Context, addressable *duckv1.AddressableType) ([]*eventingv1alpha1.Trigger, error) {
+func (c *Reconciler) createTriggers(ctx context.Context, addressable *duckv1.AddressableType, filters []atapi.Filter) ([]*eventingv1alpha1.Trigger, error) {
 	logger := logging.FromContext(ctx)
 
-	triggers, err := resources.MakeTriggers(addressable)
+	triggers, err := c.desiredTriggers(ctx, addressable, filters)
 	if err != nil {
 		return nil, err
 	}
 	var retErr error
 	createdTriggers := []*eventingv1alpha1.Trigger(nil)
+	adopted := sets.NewString()
 	for _, trigger := range triggers {
-		createdTrigger, err := c.eventingClientSet.EventingV1alpha1().Triggers(addressable.Namespace).Create(trigger)
+		createdTrigger, err := c.createOrAdoptTrigger(ctx, addressable, trigger, adopted)
 		if err != nil {
 			logger.Errorf("failed to create trigger: %+v, %s", trigger, err.Error())
 			retErr = err
@@ -164,62 +434,269 @@ func (c *Reconciler) createTriggers(ctx context.Context, addressable *duckv1.Add
 	return createdTriggers, retErr
 }
 
-func triggerSemanticEquals(desiredTrigger, trigger *eventingv1alpha1.Trigger) bool {
-	return equality.Semantic.DeepEqual(desiredTrigger.Spec, trigger.Spec) &&
-		equality.Semantic.DeepEqual(desiredTrigger.ObjectMeta.Labels, trigger.ObjectMeta.Labels)
-}
+// desiredTriggers makes the Triggers for the Addressable, with the filters of
+// its profiles followed by the given ones of its bindings and namespace, and
+// drops the ones denied by an AutoTriggerPolicy, reporting each denial as an
+// event.
+func (c *Reconciler) desiredTriggers(ctx context.Context, addressable *duckv1.AddressableType, bound []atapi.Filter) ([]*eventingv1alpha1.Trigger, error) {
+	logger := logging.FromContext(ctx)
 
-func extractTriggerLike(triggers []*eventingv1alpha1.Trigger, like *eventingv1alpha1.Trigger) ([]*eventingv1alpha1.Trigger, *eventingv1alpha1.Trigger) {
-	for i, trigger := range triggers {
-		if triggerSemanticEquals(like, trigger) {
-			triggers = append(triggers[:i], triggers[i+1:]...)
-			return triggers, trigger
+	filters := make([]atapi.Filter, 0, len(bound))
+	for _, name := range resources.ProfileNames(addressable) {
+		_, span := tracing.StartSpan(ctx, "GetAutoTriggerProfile", c.gvr, addressable.Namespace, name)
+		profile, err := c.profileLister.AutoTriggerProfiles(addressable.Namespace).Get(name)
+		tracing.EndSpan(span, ignoreNotFound(err))
+		if apierrs.IsNotFound(err) {
+			// The Addressable is looked at again once the profile is created.
+			logger.Infof("%s/%s: AutoTriggerProfile %q not found", addressable.Namespace, addressable.Name, name)
+			if c.recorder != nil {
+				c.recorder.Eventf(addressable, corev1.EventTypeWarning, "ProfileNotFound", "AutoTriggerProfile %q not found", name)
+			}
+			continue
+		} else if err != nil {
+			return nil, err
 		}
+		filters = append(filters, profile.Spec.Filters...)
 	}
-	return triggers, nil
-}
-
-func (c *Reconciler) reconcileTriggers(ctx context.Context, addressable *duckv1.AddressableType, existingTriggers []*eventingv1alpha1.Trigger) ([]*eventingv1alpha1.Trigger, error) {
-	logger := logging.FromContext(ctx)
 
-	_ = logger
+	filters = append(filters, bound...)
 
-	desiredTriggers, err := resources.MakeTriggers(addressable)
+	triggers, pending, err := resources.MakeTriggers(addressable, config.FromContextOrDefaults(ctx).AutoTrigger, filters...)
 	if err != nil {
+		c.stats.ReportFilterParseFailure(c.gvr.String())
 		return nil, err
 	}
-	if len(desiredTriggers) == 0 {
-		// No auto-triggers for this service.
-		return nil, nil
+	for _, filter := range pending {
+		// The Addressable is enqueued again once it has an address.
+		logger.Debugf("%s/%s: waiting for an address to subscribe to Broker %s", addressable.Namespace, addressable.Name, filter.Broker)
 	}
 
-	triggers := []*eventingv1alpha1.Trigger(nil)
+	if triggers, err = c.scopedTriggers(ctx, addressable, triggers); err != nil {
+		return nil, err
+	}
+
+	_, span := tracing.StartSpan(ctx, "ListAutoTriggerPolicies", c.gvr, addressable.Namespace, addressable.Name)
+	policies, err := c.policyLister.List(labels.Everything())
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	} else if len(policies) == 0 {
+		return triggers, nil
+	}
+
+	_, span = tracing.StartSpan(ctx, "GetNamespace", c.gvr, addressable.Namespace, addressable.Name)
+	namespace, err := c.namespaceLister.Get(addressable.Namespace)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, desiredTrigger := range desiredTriggers {
+	triggers, denials, invalid := resources.ApplyPolicies(policies, namespace, triggers)
+	for _, policy := range invalid {
+		logger.Infof("%s/%s: %s", addressable.Namespace, addressable.Name, policy)
+		if c.recorder != nil {
+			c.recorder.Eventf(policy.Policy, corev1.EventTypeWarning, "InvalidNamespaceSelector", "Invalid namespaceSelector: %v", policy.Err)
+		}
+	}
+	for _, denial := range denials {
+		logger.Infof("%s/%s: %s", addressable.Namespace, addressable.Name, denial)
+		if c.recorder != nil {
+			c.recorder.Event(addressable, corev1.EventTypeWarning, "TriggerDenied", denial.String())
+		}
+	}
+	return triggers, nil
+}
 
-		var trigger *eventingv1alpha1.Trigger
-		existingTriggers, trigger = extractTriggerLike(existingTriggers, desiredTrigger)
+// scopedTriggers drops the Triggers in namespaces out of the scope of the
+// controller, reporting each as an event. A controller limited to a list of
+// namespaces only sees the Triggers of the namespace of the Addressable, so it
+// makes none in other namespaces.
+func (c *Reconciler) scopedTriggers(ctx context.Context, addressable *duckv1.AddressableType, triggers []*eventingv1alpha1.Trigger) ([]*eventingv1alpha1.Trigger, error) {
+	logger := logging.FromContext(ctx)
 
-		if trigger == nil {
+	if c.scope == nil {
+		return triggers, nil
+	}
+	scoped := make([]*eventingv1alpha1.Trigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		ok := trigger.Namespace == addressable.Namespace
+		if !ok && !c.scope.Namespaced() {
 			var err error
-			trigger, err = c.eventingClientSet.EventingV1alpha1().Triggers(addressable.Namespace).Create(desiredTrigger)
-			if err != nil {
+			if ok, err = c.scope.Allows(c.namespaceLister, trigger.Namespace); err != nil {
 				return nil, err
 			}
 		}
+		if !ok {
+			msg := fmt.Sprintf("Broker %s/%s is out of the scope of the controller", trigger.Namespace, trigger.Spec.Broker)
+			logger.Infof("%s/%s: %s", addressable.Namespace, addressable.Name, msg)
+			if c.recorder != nil {
+				c.recorder.Event(addressable, corev1.EventTypeWarning, "TriggerOutOfScope", msg)
+			}
+			continue
+		}
+		scoped = append(scoped, trigger)
+	}
+	return scoped, nil
+}
+
+// reconcileTriggers brings the existing Triggers of the Addressable in line
+// with the desired ones, following the plan made by atapi.Diff.
+func (c *Reconciler) reconcileTriggers(ctx context.Context, addressable *duckv1.AddressableType, existingTriggers []*eventingv1alpha1.Trigger, filters []atapi.Filter) ([]*eventingv1alpha1.Trigger, error) {
+	logger := logging.FromContext(ctx)
 
+	desiredTriggers, err := c.desiredTriggers(ctx, addressable, filters)
+	if err != nil {
+		return nil, err
+	}
+	plan := atapi.Diff(existingTriggers, desiredTriggers)
+	triggers := plan.Keep
+
+	for _, trigger := range plan.Update {
+		// Put back what was changed by hand, or what changed on the
+		// Addressable since.
+		logger.Infof("reverting drift of Trigger %s/%s", trigger.Namespace, trigger.Name)
+		updated, err := c.updateTrigger(ctx, trigger)
+		if err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, updated)
+	}
+
+	adopted := sets.NewString()
+	for _, desiredTrigger := range plan.Create {
+		trigger, err := c.createOrAdoptTrigger(ctx, addressable, desiredTrigger, adopted)
+		if err != nil {
+			return nil, err
+		}
 		triggers = append(triggers, trigger)
 	}
 
-	// Delete all the remaining triggers.
-	for _, trigger := range existingTriggers {
-		err := c.eventingClientSet.EventingV1alpha1().Triggers(addressable.Namespace).Delete(trigger.Name, &metav1.DeleteOptions{})
+	for _, trigger := range plan.Delete {
+		err := c.deleteTrigger(ctx, trigger)
 		if err != nil {
 			logger.Errorf("failed to delete Trigger %q: %v", trigger.Name, err)
 		}
 	}
 
-	// TODO: we need to look at the remaining existingTriggers and delete the ones that are leftover.
-
 	return triggers, nil
 }
+
+// createOrAdoptTrigger creates the desired Trigger or, when adoption is on,
+// takes over a Trigger written by hand that does the same. The keys of the
+// Triggers adopted so far are kept in adopted, so each is only adopted once.
+func (c *Reconciler) createOrAdoptTrigger(ctx context.Context, addressable *duckv1.AddressableType, desired *eventingv1alpha1.Trigger, adopted sets.String) (*eventingv1alpha1.Trigger, error) {
+	if !config.FromContextOrDefaults(ctx).AutoTrigger.AdoptTriggers {
+		return c.createTrigger(ctx, desired)
+	}
+
+	_, span := tracing.StartSpan(ctx, "ListTriggers", triggersGVR, desired.Namespace, "")
+	candidates, err := c.triggerLister.Triggers(desired.Namespace).List(labels.Everything())
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		key := candidate.Namespace + "/" + candidate.Name
+		if adopted.Has(key) || !resources.Adoptable(candidate, desired) {
+			continue
+		}
+		adopted.Insert(key)
+
+		logging.FromContext(ctx).Infof("adopting Trigger %s/%s", candidate.Namespace, candidate.Name)
+		trigger, err := c.adoptTrigger(ctx, atapi.Adopt(candidate, desired))
+		if err == nil && c.recorder != nil {
+			c.recorder.Eventf(addressable, corev1.EventTypeNormal, "TriggerAdopted", "Adopted Trigger %s/%s", trigger.Namespace, trigger.Name)
+		}
+		return trigger, err
+	}
+	return c.createTrigger(ctx, desired)
+}
+
+// createTrigger creates the Trigger and reports it.
+func (c *Reconciler) createTrigger(ctx context.Context, trigger *eventingv1alpha1.Trigger) (*eventingv1alpha1.Trigger, error) {
+	if dryrun.Enabled(ctx) {
+		c.reportDryRun(ctx, metrics.OperationCreate, trigger)
+		return trigger, nil
+	}
+	_, span := tracing.StartSpan(ctx, "CreateTrigger", triggersGVR, trigger.Namespace, trigger.Name)
+	created, err := c.eventingClientSet.EventingV1alpha1().Triggers(trigger.Namespace).Create(trigger)
+	tracing.EndSpan(span, err)
+	if err == nil {
+		c.stats.ReportTriggerOperation(c.gvr.String(), metrics.OperationCreate)
+	}
+	return created, err
+}
+
+// updateTrigger updates the Trigger and reports it.
+func (c *Reconciler) updateTrigger(ctx context.Context, trigger *eventingv1alpha1.Trigger) (*eventingv1alpha1.Trigger, error) {
+	if dryrun.Enabled(ctx) {
+		c.reportDryRun(ctx, metrics.OperationUpdate, trigger)
+		return trigger, nil
+	}
+	_, span := tracing.StartSpan(ctx, "UpdateTrigger", triggersGVR, trigger.Namespace, trigger.Name)
+	updated, err := c.eventingClientSet.EventingV1alpha1().Triggers(trigger.Namespace).Update(trigger)
+	tracing.EndSpan(span, err)
+	if err == nil {
+		c.stats.ReportTriggerOperation(c.gvr.String(), metrics.OperationUpdate)
+	}
+	return updated, err
+}
+
+// adoptTrigger updates a Trigger taken over by autotrigger and reports it.
+func (c *Reconciler) adoptTrigger(ctx context.Context, trigger *eventingv1alpha1.Trigger) (*eventingv1alpha1.Trigger, error) {
+	if dryrun.Enabled(ctx) {
+		c.reportDryRun(ctx, metrics.OperationAdopt, trigger)
+		return trigger, nil
+	}
+	_, span := tracing.StartSpan(ctx, "AdoptTrigger", triggersGVR, trigger.Namespace, trigger.Name)
+	updated, err := c.eventingClientSet.EventingV1alpha1().Triggers(trigger.Namespace).Update(trigger)
+	tracing.EndSpan(span, err)
+	if err == nil {
+		c.stats.ReportTriggerOperation(c.gvr.String(), metrics.OperationAdopt)
+	}
+	return updated, err
+}
+
+// deleteTrigger deletes the Trigger and reports it.
+func (c *Reconciler) deleteTrigger(ctx context.Context, trigger *eventingv1alpha1.Trigger) error {
+	if dryrun.Enabled(ctx) {
+		c.reportDryRun(ctx, metrics.OperationDelete, trigger)
+		return nil
+	}
+	_, span := tracing.StartSpan(ctx, "DeleteTrigger", triggersGVR, trigger.Namespace, trigger.Name)
+	err := c.eventingClientSet.EventingV1alpha1().Triggers(trigger.Namespace).Delete(trigger.Name, &metav1.DeleteOptions{})
+	tracing.EndSpan(span, err)
+	if err == nil {
+		c.stats.ReportTriggerOperation(c.gvr.String(), metrics.OperationDelete)
+	}
+	return err
+}
+
+// reportDryRun reports a change to a Trigger that is not made in dry-run mode,
+// with a log line, a metric and an event on the Addressable it is for.
+func (c *Reconciler) reportDryRun(ctx context.Context, operation string, trigger *eventingv1alpha1.Trigger) {
+	name := trigger.Name
+	if name == "" {
+		name = trigger.GenerateName + "*"
+	}
+	var ref runtime.Object
+	if _, owner, ok := resources.Owner(trigger); ok {
+		ref = &corev1.ObjectReference{
+			APIVersion: c.gvr.GroupVersion().String(),
+			Kind:       c.gk.Kind,
+			Namespace:  owner.Namespace,
+			Name:       owner.Name,
+		}
+	}
+	reporter := dryrun.Reporter{Resource: c.gvr.String(), Stats: c.stats, Recorder: c.recorder}
+	reporter.Report(ctx, ref, operation, "Trigger", trigger.Namespace, name)
+}
+
+// ignoreNotFound drops the errors for objects that are missing, which are
+// expected and not a failure of the span that got them.
+func ignoreNotFound(err error) error {
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,403 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autotrigger
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes"
+	etresources "github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes/resources"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline"
+	pipelineresources "github.com/n3wscott/autotrigger/pkg/reconciler/pipeline/resources"
+	. "github.com/n3wscott/autotrigger/pkg/reconciler/testing"
+)
+
+const (
+	testNS      = "test-namespace"
+	remoteNS    = "remote-namespace"
+	serviceName = "test-service"
+)
+
+var (
+	servicesGVR       = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "services"}
+	configurationsGVK = schema.GroupVersionKind{Group: "serving.knative.dev", Version: "v1", Kind: "Configuration"}
+)
+
+type addressableOption func(*duckv1.AddressableType)
+
+func newService(opts ...addressableOption) *duckv1.AddressableType {
+	a := &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      serviceName,
+			UID:       "test-service-uid",
+		},
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func withLabel(key, value string) addressableOption {
+	return func(a *duckv1.AddressableType) {
+		if a.Labels == nil {
+			a.Labels = make(map[string]string)
+		}
+		a.Labels[key] = value
+	}
+}
+
+func withFilter(filter string) addressableOption {
+	return func(a *duckv1.AddressableType) {
+		if a.Annotations == nil {
+			a.Annotations = make(map[string]string)
+		}
+		a.Annotations[resources.FilterAnnotation] = filter
+	}
+}
+
+func withAnnotation(key, value string) addressableOption {
+	return func(a *duckv1.AddressableType) {
+		if a.Annotations == nil {
+			a.Annotations = make(map[string]string)
+		}
+		a.Annotations[key] = value
+	}
+}
+
+func withAddress(a *duckv1.AddressableType) {
+	a.Status.Address = &duckv1.Addressable{
+		URL: apis.HTTP("test-service.test-namespace.svc.cluster.local"),
+	}
+}
+
+func withOwner(gvk schema.GroupVersionKind, name string) addressableOption {
+	return func(a *duckv1.AddressableType) {
+		apiVersion, kind := gvk.ToAPIVersionAndKind()
+		a.OwnerReferences = append(a.OwnerReferences, metav1.OwnerReference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Name:       name,
+		})
+	}
+}
+
+// desiredTriggers makes the Triggers the Reconciler is expected to create for
+// the Addressable, with the default configuration.
+func desiredTriggers(t *testing.T, a *duckv1.AddressableType) []runtime.Object {
+	triggers, _, err := resources.MakeTriggers(a, config.FromContextOrDefaults(context.Background()).AutoTrigger)
+	if err != nil {
+		t.Fatalf("MakeTriggers() = %v", err)
+	}
+	objs := make([]runtime.Object, 0, len(triggers))
+	for _, trigger := range triggers {
+		objs = append(objs, trigger)
+	}
+	return objs
+}
+
+// existingTrigger makes the Trigger the Reconciler would have created for the
+// filter of the Addressable, under the name the API server gave it.
+func existingTrigger(t *testing.T, a *duckv1.AddressableType, name string) *eventingv1alpha1.Trigger {
+	trigger := desiredTriggers(t, a)[0].(*eventingv1alpha1.Trigger)
+	trigger.Name = name
+	return trigger
+}
+
+func withAttributes(trigger *eventingv1alpha1.Trigger, attributes map[string]string) *eventingv1alpha1.Trigger {
+	trigger = trigger.DeepCopy()
+	filter := eventingv1alpha1.TriggerFilterAttributes(attributes)
+	trigger.Spec.Filter = &eventingv1alpha1.TriggerFilter{Attributes: &filter}
+	return trigger
+}
+
+// configured returns a context with the configuration of the ConfigMap data.
+func configured(t *testing.T, data map[string]string) context.Context {
+	cfg, err := config.NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.ConfigName},
+		Data:       data,
+	})
+	if err != nil {
+		t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+	}
+	return config.ToContext(context.Background(), &config.Config{AutoTrigger: cfg})
+}
+
+func dryRun(t *testing.T) context.Context {
+	return configured(t, map[string]string{"dry-run": "true"})
+}
+
+func TestReconcile(t *testing.T) {
+	labeled := newService(withLabel(resources.AutoTriggerLabel, "true"), withFilter(`[{"type":"dev.knative.foo"}]`))
+	remote := newService(withLabel(resources.AutoTriggerLabel, "true"), withFilter(`[{"broker":"remote-namespace/default"}]`), withAddress)
+	unlabeled := newService(withFilter(`[{"type":"dev.knative.foo"}]`))
+	child := newService(withLabel(resources.AutoTriggerLabel, "true"), withFilter(`[{"type":"dev.knative.foo"}]`),
+		withOwner(configurationsGVK, "parent"))
+
+	current := existingTrigger(t, labeled, "test-service-abcde")
+	drifted := withAttributes(current, map[string]string{"type": "dev.knative.bar"})
+	extra := withAttributes(existingTrigger(t, labeled, "test-service-fghij"), map[string]string{"source": "elsewhere"})
+	extra.Spec.Broker = "other"
+
+	// A Trigger labeled with the owner of the Addressable by hand.
+	unmanaged := existingTrigger(t, labeled, "test-service-byhand")
+	delete(unmanaged.Labels, config.ManagedByLabel)
+
+	// A Trigger another controller made for the Addressable, like the one
+	// feeding a pipeline.
+	controlled := &eventingv1alpha1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNS,
+			Name:            "test-service-ingress",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(unlabeled, unlabeled.GroupVersionKind())},
+			Labels:          map[string]string{pipelineresources.ManagedLabel: "true"},
+		},
+	}
+
+	parseErr := func(t *testing.T, _ *TableRow, f Fakes) {
+		if got := f.Stats.FilterParseFailures; got != 1 {
+			t.Errorf("FilterParseFailures = %d, wanted 1", got)
+		}
+	}
+	_, defaultErr := resources.ParseDefaultFilter("{")
+
+	badBinding := &autotriggerv1alpha1.AutoTriggerBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: "bad-selector"},
+		Spec: autotriggerv1alpha1.AutoTriggerBindingSpec{
+			Subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Selector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}},
+				},
+			},
+		},
+	}
+	_, selectorErr := resources.BindingSelector(badBinding)
+
+	// A member of a Sequence that declares the types it produces.
+	producer := newService(withLabel(resources.AutoTriggerLabel, "true"), withFilter(`[{"type":"dev.knative.foo"}]`),
+		withAnnotation("pipeline.eventing.knative.dev/sequence", `{"name":"flow","step":1}`),
+		withAnnotation("eventing.knative.dev/produces", `[{"type":"dev.knative.bar","source":"/bar"}]`))
+	producedTypes, err := etresources.MakeEventTypes(producer, resources.DefaultBroker)
+	if err != nil {
+		t.Fatalf("MakeEventTypes() = %v", err)
+	}
+
+	badPolicy := &autotriggerv1alpha1.AutoTriggerPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "bad-selector"},
+		Spec: autotriggerv1alpha1.AutoTriggerPolicySpec{
+			NamespaceSelector: badBinding.Spec.Subject.Selector,
+			AllowedBrokers:    []string{"none"},
+		},
+	}
+
+	table := TableTest{{
+		Name: "bad key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "addressable not found",
+		Key:  testNS + "/" + serviceName,
+	}, {
+		Name:    "addressable not found, remote triggers deleted",
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{existingTrigger(t, remote, "remote-trigger")},
+		WantDeletes: []Action{{
+			Resource:  "triggers",
+			Namespace: remoteNS,
+			Name:      "remote-trigger",
+		}},
+	}, {
+		Name:        "create triggers",
+		Key:         testNS + "/" + serviceName,
+		Objects:     []runtime.Object{labeled},
+		WantCreates: desiredTriggers(t, labeled),
+	}, {
+		Name:        "create remote trigger",
+		Key:         testNS + "/" + serviceName,
+		Objects:     []runtime.Object{remote},
+		WantCreates: desiredTriggers(t, remote),
+	}, {
+		Name:    "triggers up to date",
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{labeled, current},
+	}, {
+		Name:        "revert drifted trigger",
+		Key:         testNS + "/" + serviceName,
+		Objects:     []runtime.Object{labeled, drifted},
+		WantUpdates: []runtime.Object{current},
+	}, {
+		Name:    "delete extra trigger",
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{labeled, current, extra},
+		WantDeletes: []Action{{
+			Resource:  "triggers",
+			Namespace: testNS,
+			Name:      "test-service-fghij",
+		}},
+	}, {
+		Name:    "delete triggers once unlabeled",
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{unlabeled, existingTrigger(t, labeled, "test-service-abcde")},
+		WantDeletes: []Action{{
+			Resource:  "triggers",
+			Namespace: testNS,
+			Name:      "test-service-abcde",
+		}},
+	}, {
+		Name:    "triggers made by others left alone",
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{unlabeled, controlled},
+	}, {
+		Name:    "unmanaged triggers left alone once unlabeled",
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{unlabeled, unmanaged},
+	}, {
+		Name:    "namespace not opted in, triggers deleted",
+		Ctx:     configured(t, map[string]string{"namespace-opt-in": "true"}),
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{labeled, current, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNS}}},
+		WantDeletes: []Action{{
+			Resource:  "triggers",
+			Namespace: testNS,
+			Name:      "test-service-abcde",
+		}},
+	}, {
+		Name: "namespace opted in",
+		Ctx:  configured(t, map[string]string{"namespace-opt-in": "true"}),
+		Key:  testNS + "/" + serviceName,
+		Objects: []runtime.Object{labeled, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   testNS,
+				Labels: map[string]string{resources.NamespaceEnabledLabel: "true"},
+			},
+		}},
+		WantCreates: desiredTriggers(t, labeled),
+	}, {
+		Name:           "filter parse error",
+		Key:            testNS + "/" + serviceName,
+		Objects:        []runtime.Object{newService(withLabel(resources.AutoTriggerLabel, "true"), withFilter("{"))},
+		WantErr:        true,
+		PostConditions: []func(*testing.T, *TableRow, Fakes){parseErr},
+	}, {
+		Name: "namespace default filter parse error",
+		Key:  testNS + "/" + serviceName,
+		Objects: []runtime.Object{labeled, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        testNS,
+				Annotations: map[string]string{resources.DefaultFilterAnnotation: "{"},
+			},
+		}},
+		WantCreates:    desiredTriggers(t, labeled),
+		WantEvents:     []string{Eventf(corev1.EventTypeWarning, "DefaultFilterInvalid", defaultErr.Error())},
+		PostConditions: []func(*testing.T, *TableRow, Fakes){parseErr},
+	}, {
+		Name:        "binding with an invalid selector skipped",
+		Key:         testNS + "/" + serviceName,
+		Objects:     []runtime.Object{labeled, badBinding},
+		WantCreates: desiredTriggers(t, labeled),
+		WantEvents:  []string{Eventf(corev1.EventTypeWarning, "InvalidSelector", "Invalid selector: %v", selectorErr)},
+	}, {
+		Name:        "policy with an invalid namespace selector skipped",
+		Key:         testNS + "/" + serviceName,
+		Objects:     []runtime.Object{labeled, badPolicy, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNS}}},
+		WantCreates: desiredTriggers(t, labeled),
+		WantEvents:  []string{Eventf(corev1.EventTypeWarning, "InvalidNamespaceSelector", "Invalid namespaceSelector: %v", selectorErr)},
+	}, {
+		Name:    "child of an addressable skipped",
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{child, existingTrigger(t, labeled, "test-service-abcde")},
+	}, {
+		Name:         "create fails",
+		Key:          testNS + "/" + serviceName,
+		Objects:      []runtime.Object{labeled},
+		WithReactors: []Reactor{InduceFailure(VerbCreate, "triggers")},
+		WantErr:      true,
+		WantCreates:  desiredTriggers(t, labeled),
+	}, {
+		Name:    "dry run",
+		Ctx:     dryRun(t),
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{labeled},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would %s Trigger %s/%s", "create", testNS, "test-service-*"),
+		},
+	}, {
+		Name:    "dry run, pipelines and event types",
+		Ctx:     dryRun(t),
+		Key:     testNS + "/" + serviceName,
+		Objects: []runtime.Object{producer},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would %s Trigger %s/%s", "create", testNS, "test-service-*"),
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would %s Sequence %s/%s", "create", testNS, "flow"),
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would %s EventType %s/%s", "create", testNS, producedTypes[0].Name),
+		},
+	}}
+
+	table.Test(t, func(t *testing.T, r *TableRow, f Fakes) controller.Reconciler {
+		dryRun := dryrun.Reporter{
+			Resource: servicesGVR.String(),
+			Stats:    f.Stats,
+			Recorder: f.Recorder,
+		}
+		return &Reconciler{
+			addressableLister: f.Listers.GetAddressableLister(servicesGVR),
+			info:              AddressableInfo{configurationsGVK},
+			eventingClientSet: f.Client,
+			triggerLister:     f.Listers.GetTriggerLister(),
+			triggerIndexer:    f.Listers.GetTriggerIndexer(),
+			gvr:               servicesGVR,
+			gk:                schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"},
+			eventTypes: &eventtypes.Reconciler{
+				EventingClientSet: f.Client,
+				EventTypeLister:   f.Listers.GetEventTypeLister(),
+				DryRun:            dryRun,
+			},
+			pipelines: &pipeline.Membership{
+				EventingClientSet: f.Client,
+				SequenceLister:    f.Listers.GetSequenceLister(),
+				ParallelLister:    f.Listers.GetParallelLister(),
+				DryRun:            dryRun,
+			},
+			profileLister:   f.Listers.GetAutoTriggerProfileLister(),
+			bindingLister:   f.Listers.GetAutoTriggerBindingLister(),
+			policyLister:    f.Listers.GetAutoTriggerPolicyLister(),
+			namespaceLister: f.Listers.GetNamespaceLister(),
+			recorder:        f.Recorder,
+			stats:           f.Stats,
+		}
+	})
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+)
+
+const (
+	// ConfigName is the name of the ConfigMap holding the autotrigger
+	// configuration.
+	ConfigName = "config-autotrigger"
+
+	labelsIncludeKey      = "labels.include"
+	labelsExcludeKey      = "labels.exclude"
+	annotationsIncludeKey = "annotations.include"
+	annotationsExcludeKey = "annotations.exclude"
+	dryRunKey             = "dry-run"
+	adoptTriggersKey      = "adopt-triggers"
+	labeledOnlyKey        = "watch.labeled-only"
+	namespaceOptInKey     = "namespace-opt-in"
+
+	// ReservedPrefix is the prefix of the labels autotrigger sets on the
+	// Triggers it makes. Keys with it are never propagated.
+	ReservedPrefix = autotrigger.ReservedPrefix
+
+	// ManagedByLabel is set to ManagedBy on every Trigger autotrigger makes.
+	ManagedByLabel = autotrigger.ManagedByLabel
+	ManagedBy      = autotrigger.ManagedBy
+)
+
+// AutoTrigger is the configuration of the autotrigger controllers.
+type AutoTrigger struct {
+	// Labels selects the labels of an Addressable copied onto its Triggers.
+	Labels Propagation
+	// Annotations selects the annotations of an Addressable copied onto its
+	// Triggers.
+	Annotations Propagation
+	// DryRun makes the controllers report the changes they would make to
+	// Triggers instead of making them.
+	DryRun bool
+	// AdoptTriggers makes the controllers take over the Triggers written by
+	// hand that match the ones they would create.
+	AdoptTriggers bool
+	// NamespaceOptIn makes the controllers act only in the namespaces with the
+	// eventing.knative.dev/autotrigger-enabled label, and clean up in the
+	// others.
+	NamespaceOptIn bool
+	// LabeledOnly makes the controllers list and watch only the Addressables
+	// with the autotrigger label, instead of every one of their kind. It is
+	// read when the controller starts, so a change takes a restart.
+	LabeledOnly bool
+	// WorkQueue rate limits the workqueue of each Addressable controller. It
+	// is read when the controller starts, so a change takes a restart.
+	WorkQueue RateLimit
+	// Writes is the budget of writes to the eventing APIs shared by all the
+	// controllers. A change applies right away.
+	Writes Budget
+}
+
+// Propagation selects keys by prefix.
+type Propagation = autotrigger.Propagation
+
+// Synthesis returns the part of the configuration autotrigger.Synthesize
+// uses.
+func (at *AutoTrigger) Synthesis() autotrigger.Config {
+	return autotrigger.Config{
+		Labels:      at.Labels,
+		Annotations: at.Annotations,
+	}
+}
+
+// NewAutoTriggerFromConfigMap creates an AutoTrigger from the supplied
+// ConfigMap, using the defaults for the keys it does not set.
+func NewAutoTriggerFromConfigMap(config *corev1.ConfigMap) (*AutoTrigger, error) {
+	at := defaultConfig()
+	for key, field := range map[string]*[]string{
+		labelsIncludeKey:      &at.Labels.Include,
+		labelsExcludeKey:      &at.Labels.Exclude,
+		annotationsIncludeKey: &at.Annotations.Include,
+		annotationsExcludeKey: &at.Annotations.Exclude,
+	} {
+		if raw, ok := config.Data[key]; ok {
+			*field = splitList(raw)
+		}
+	}
+	for key, field := range map[string]*bool{
+		dryRunKey:         &at.DryRun,
+		adoptTriggersKey:  &at.AdoptTriggers,
+		labeledOnlyKey:    &at.LabeledOnly,
+		namespaceOptInKey: &at.NamespaceOptIn,
+	} {
+		if raw, ok := config.Data[key]; ok {
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %q: %v", key, err)
+			}
+			*field = b
+		}
+	}
+	if err := parseRateLimits(config.Data, at); err != nil {
+		return nil, err
+	}
+	return at, nil
+}
+
+func defaultConfig() *AutoTrigger {
+	return &AutoTrigger{
+		Labels: Propagation{
+			Include: []string{"*"},
+			Exclude: []string{
+				"eventing.knative.dev/autotrigger",
+				"serving.knative.dev/",
+			},
+		},
+		Annotations: Propagation{
+			Exclude: []string{
+				"kubectl.kubernetes.io/",
+				"trigger.eventing.knative.dev/",
+			},
+		},
+		WorkQueue: defaultWorkQueue(),
+		Writes:    defaultWrites(),
+	}
+}
+
+func splitList(raw string) []string {
+	var list []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPropagation(t *testing.T) {
+	in := map[string]string{
+		"app":                              "foo",
+		"team.example.com/owner":           "payments",
+		"eventing.knative.dev/autotrigger": "true",
+		"serving.knative.dev/service":      "foo",
+		ReservedPrefix + "owner-uid":       "abc-123",
+		ManagedByLabel:                     "helm",
+	}
+
+	tests := map[string]struct {
+		data map[string]string
+		want map[string]string
+	}{
+		"defaults": {
+			want: map[string]string{
+				"app":                    "foo",
+				"team.example.com/owner": "payments",
+			},
+		},
+		"include": {
+			data: map[string]string{labelsIncludeKey: "team.example.com/"},
+			want: map[string]string{
+				"team.example.com/owner": "payments",
+			},
+		},
+		"include everything": {
+			data: map[string]string{labelsIncludeKey: "*", labelsExcludeKey: ""},
+			want: map[string]string{
+				"app":                              "foo",
+				"team.example.com/owner":           "payments",
+				"eventing.knative.dev/autotrigger": "true",
+				"serving.knative.dev/service":      "foo",
+			},
+		},
+		"exclude": {
+			data: map[string]string{labelsExcludeKey: "app, team.example.com/"},
+			want: map[string]string{
+				"eventing.knative.dev/autotrigger": "true",
+				"serving.knative.dev/service":      "foo",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			at, err := NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+				Data:       tc.data,
+			})
+			if err != nil {
+				t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, at.Labels.Filter(in)); diff != "" {
+				t.Errorf("Labels.Filter() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	tests := map[string]struct {
+		data    map[string]string
+		want    bool
+		wantErr bool
+	}{
+		"default": {},
+		"on": {
+			data: map[string]string{dryRunKey: "true"},
+			want: true,
+		},
+		"off": {
+			data: map[string]string{dryRunKey: "false"},
+		},
+		"invalid": {
+			data:    map[string]string{dryRunKey: "maybe"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			at, err := NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+				Data:       tc.data,
+			})
+			if tc.wantErr {
+				if err == nil {
+					t.Error("NewAutoTriggerFromConfigMap() = nil, wanted an error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+			}
+			if at.DryRun != tc.want {
+				t.Errorf("DryRun = %v, wanted %v", at.DryRun, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdoptTriggers(t *testing.T) {
+	at, err := NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+		Data:       map[string]string{adoptTriggersKey: "true"},
+	})
+	if err != nil {
+		t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+	}
+	if !at.AdoptTriggers || at.DryRun {
+		t.Errorf("AdoptTriggers, DryRun = %v, %v, wanted true, false", at.AdoptTriggers, at.DryRun)
+	}
+}
+
+func TestLabeledOnly(t *testing.T) {
+	at, err := NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+		Data:       map[string]string{labeledOnlyKey: "true"},
+	})
+	if err != nil {
+		t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+	}
+	if !at.LabeledOnly {
+		t.Error("LabeledOnly = false, wanted true")
+	}
+}
+
+func TestNamespaceOptIn(t *testing.T) {
+	at, err := NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+		Data:       map[string]string{namespaceOptInKey: "true"},
+	})
+	if err != nil {
+		t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+	}
+	if !at.NamespaceOptIn {
+		t.Error("NamespaceOptIn = false, wanted true")
+	}
+}
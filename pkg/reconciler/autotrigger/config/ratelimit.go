@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	workQueueBaseDelayKey = "workqueue.base-delay"
+	workQueueMaxDelayKey  = "workqueue.max-delay"
+	workQueueQPSKey       = "workqueue.qps"
+	workQueueBurstKey     = "workqueue.burst"
+	writesQPSKey          = "writes.qps"
+	writesBurstKey        = "writes.burst"
+)
+
+// RateLimit configures the rate limiter of a workqueue: the retries of each
+// key back off exponentially from BaseDelay to MaxDelay, and all the keys
+// share a bucket of QPS and Burst.
+type RateLimit struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Budget
+}
+
+// Budget is a token bucket refilled at QPS, holding up to Burst tokens. A QPS
+// of zero lifts the limit.
+type Budget struct {
+	QPS   float64
+	Burst int
+}
+
+func (b Budget) validate(qpsKey, burstKey string) error {
+	if b.QPS < 0 {
+		return fmt.Errorf("%q must not be negative, was %v", qpsKey, b.QPS)
+	}
+	if b.QPS > 0 && b.Burst < 1 {
+		return fmt.Errorf("%q must be at least 1 when %q is set, was %d", burstKey, qpsKey, b.Burst)
+	}
+	return nil
+}
+
+// The workqueue defaults are the ones of workqueue.DefaultControllerRateLimiter.
+func defaultWorkQueue() RateLimit {
+	return RateLimit{
+		BaseDelay: 5 * time.Millisecond,
+		MaxDelay:  1000 * time.Second,
+		Budget:    Budget{QPS: 10, Burst: 100},
+	}
+}
+
+func defaultWrites() Budget {
+	return Budget{QPS: 20, Burst: 40}
+}
+
+func parseRateLimits(data map[string]string, at *AutoTrigger) error {
+	for key, field := range map[string]*time.Duration{
+		workQueueBaseDelayKey: &at.WorkQueue.BaseDelay,
+		workQueueMaxDelayKey:  &at.WorkQueue.MaxDelay,
+	} {
+		if raw, ok := data[key]; ok {
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q: %v", key, err)
+			}
+			*field = d
+		}
+	}
+	for key, field := range map[string]*float64{
+		workQueueQPSKey: &at.WorkQueue.QPS,
+		writesQPSKey:    &at.Writes.QPS,
+	} {
+		if raw, ok := data[key]; ok {
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q: %v", key, err)
+			}
+			*field = f
+		}
+	}
+	for key, field := range map[string]*int{
+		workQueueBurstKey: &at.WorkQueue.Burst,
+		writesBurstKey:    &at.Writes.Burst,
+	} {
+		if raw, ok := data[key]; ok {
+			i, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q: %v", key, err)
+			}
+			*field = i
+		}
+	}
+
+	if at.WorkQueue.BaseDelay <= 0 || at.WorkQueue.MaxDelay < at.WorkQueue.BaseDelay {
+		return fmt.Errorf("%q must be positive and at most %q, were %v and %v",
+			workQueueBaseDelayKey, workQueueMaxDelayKey, at.WorkQueue.BaseDelay, at.WorkQueue.MaxDelay)
+	}
+	if err := at.WorkQueue.validate(workQueueQPSKey, workQueueBurstKey); err != nil {
+		return err
+	}
+	return at.Writes.validate(writesQPSKey, writesBurstKey)
+}
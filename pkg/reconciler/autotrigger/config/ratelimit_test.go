@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRateLimits(t *testing.T) {
+	tests := map[string]struct {
+		data          map[string]string
+		wantWorkQueue RateLimit
+		wantWrites    Budget
+		wantErr       bool
+	}{
+		"defaults": {
+			wantWorkQueue: defaultWorkQueue(),
+			wantWrites:    defaultWrites(),
+		},
+		"set": {
+			data: map[string]string{
+				workQueueBaseDelayKey: "10ms",
+				workQueueMaxDelayKey:  "5m",
+				workQueueQPSKey:       "2.5",
+				workQueueBurstKey:     "5",
+				writesQPSKey:          "0",
+			},
+			wantWorkQueue: RateLimit{
+				BaseDelay: 10 * time.Millisecond,
+				MaxDelay:  5 * time.Minute,
+				Budget:    Budget{QPS: 2.5, Burst: 5},
+			},
+			wantWrites: Budget{QPS: 0, Burst: defaultWrites().Burst},
+		},
+		"bad duration": {
+			data:    map[string]string{workQueueBaseDelayKey: "soon"},
+			wantErr: true,
+		},
+		"max below base": {
+			data:    map[string]string{workQueueMaxDelayKey: "1ms"},
+			wantErr: true,
+		},
+		"negative qps": {
+			data:    map[string]string{writesQPSKey: "-1"},
+			wantErr: true,
+		},
+		"no burst": {
+			data:    map[string]string{writesBurstKey: "0"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			at, err := NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+				Data:       tc.data,
+			})
+			if tc.wantErr {
+				if err == nil {
+					t.Error("NewAutoTriggerFromConfigMap() = nil, wanted an error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+			}
+			if diff := cmp.Diff(tc.wantWorkQueue, at.WorkQueue); diff != "" {
+				t.Errorf("WorkQueue (-want, +got) = %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantWrites, at.Writes); diff != "" {
+				t.Errorf("Writes (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"context"
+	"sync"
+
+	"knative.dev/pkg/configmap"
+)
+
+type cfgKey struct{}
+
+// Config holds the collection of configurations that we attach to contexts.
+type Config struct {
+	AutoTrigger *AutoTrigger
+}
+
+// FromContext extracts a Config from the provided context.
+func FromContext(ctx context.Context) *Config {
+	x, ok := ctx.Value(cfgKey{}).(*Config)
+	if ok {
+		return x
+	}
+	return nil
+}
+
+// FromContextOrDefaults is like FromContext, but when no Config is attached
+// it returns a Config populated with the defaults.
+func FromContextOrDefaults(ctx context.Context) *Config {
+	if cfg := FromContext(ctx); cfg != nil {
+		return cfg
+	}
+	return &Config{
+		AutoTrigger: defaultConfig(),
+	}
+}
+
+// ToContext attaches the provided Config to the provided context, returning
+// the new context with the Config attached.
+func ToContext(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, cfgKey{}, c)
+}
+
+// Store is a typed wrapper around configmap.UntypedStore to handle our
+// configmaps.
+type Store struct {
+	*configmap.UntypedStore
+
+	m        sync.Mutex
+	next     int
+	watchers map[int]func(*AutoTrigger)
+}
+
+// NewStore creates a new store of Configs and optionally calls functions when
+// ConfigMaps are updated.
+func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value interface{})) *Store {
+	store := &Store{
+		watchers: make(map[int]func(*AutoTrigger)),
+	}
+	store.UntypedStore = configmap.NewUntypedStore(
+		"autotrigger",
+		logger,
+		configmap.Constructors{
+			ConfigName: NewAutoTriggerFromConfigMap,
+		},
+		append(onAfterStore, store.notify)...,
+	)
+
+	return store
+}
+
+// Watch calls f with the AutoTrigger configuration each time it is stored,
+// until ctx is done. The controllers that start after the ConfigMap watcher
+// cannot watch it themselves, so they watch the Store instead.
+func (s *Store) Watch(ctx context.Context, f func(*AutoTrigger)) {
+	s.m.Lock()
+	id := s.next
+	s.next++
+	s.watchers[id] = f
+	s.m.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.m.Lock()
+		delete(s.watchers, id)
+		s.m.Unlock()
+	}()
+}
+
+func (s *Store) notify(name string, value interface{}) {
+	at, ok := value.(*AutoTrigger)
+	if name != ConfigName || !ok {
+		return
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	for _, f := range s.watchers {
+		f(at)
+	}
+}
+
+// ToContext attaches the current Config state to the provided context.
+func (s *Store) ToContext(ctx context.Context) context.Context {
+	return ToContext(ctx, s.Load())
+}
+
+// Load creates a Config from the current config state of the Store.
+func (s *Store) Load() *Config {
+	return &Config{
+		AutoTrigger: s.UntypedLoad(ConfigName).(*AutoTrigger),
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestStoreWatch(t *testing.T) {
+	store := NewStore(zap.NewNop().Sugar())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The Store calls its watchers from a separate goroutine.
+	got := make(chan bool, 2)
+	store.Watch(ctx, func(at *AutoTrigger) {
+		got <- at.NamespaceOptIn
+	})
+
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+		Data:       map[string]string{"namespace-opt-in": "true"},
+	})
+	select {
+	case optIn := <-got:
+		if !optIn {
+			t.Errorf("Watch() saw namespace-opt-in %v, wanted true", optIn)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch() was not called")
+	}
+
+	// Once the context is done, the watcher is dropped.
+	cancel()
+	if err := wait.PollImmediate(time.Millisecond, time.Second, func() (bool, error) {
+		store.m.Lock()
+		defer store.m.Unlock()
+		return len(store.watchers) == 0, nil
+	}); err != nil {
+		t.Fatalf("watcher not dropped: %v", err)
+	}
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+	})
+	select {
+	case optIn := <-got:
+		t.Errorf("Watch() saw namespace-opt-in %v after the context was done", optIn)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -2,25 +2,48 @@ package autotrigger
 
 import (
 	"context"
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/ratelimit"
 	"github.com/n3wscott/autotrigger/pkg/reconciler"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/tools/cache"
-	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
-	"knative.dev/pkg/apis/duck"
+	"k8s.io/client-go/util/workqueue"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
+	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 
 	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	eventtypeinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/eventtype"
 	triggerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/trigger"
+	parallelinformer "knative.dev/eventing/pkg/client/injection/informers/messaging/v1alpha1/parallel"
+	sequenceinformer "knative.dev/eventing/pkg/client/injection/informers/messaging/v1alpha1/sequence"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+	"knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/injection/clients/dynamicclient"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	bindinginformer "github.com/n3wscott/autotrigger/pkg/client/injection/informers/autotrigger/v1alpha1/autotriggerbinding"
+	policyinformer "github.com/n3wscott/autotrigger/pkg/client/injection/informers/autotrigger/v1alpha1/autotriggerpolicy"
+	profileinformer "github.com/n3wscott/autotrigger/pkg/client/injection/informers/autotrigger/v1alpha1/autotriggerprofile"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline"
+	"github.com/n3wscott/autotrigger/pkg/scope"
 )
 
-func NewControllerConstructor(name string, gvr schema.GroupVersionResource, info reconciler.AddressableInfo) injection.ControllerConstructor {
+func NewControllerConstructor(name string, gvr schema.GroupVersionResource, gk schema.GroupKind, info reconciler.AddressableInfo, configStore *config.Store) injection.ControllerConstructor {
 	return func(
 		ctx context.Context,
 		cmw configmap.Watcher,
@@ -28,10 +51,28 @@ func NewControllerConstructor(name string, gvr schema.GroupVersionResource, info
 		logger := logging.FromContext(ctx)
 
 		triggerInformer := triggerinformer.Get(ctx)
+		eventTypeInformer := eventtypeinformer.Get(ctx)
+		policyInformer := policyinformer.Get(ctx)
+		profileInformer := profileinformer.Get(ctx)
+		bindingInformer := bindinginformer.Get(ctx)
+		namespaceInformer := namespace.Get(ctx)
+
+		cfg := config.FromContextOrDefaults(ctx).AutoTrigger
+		if configStore != nil {
+			cfg = configStore.Load().AutoTrigger
+		}
 
-		addressinformer := &duck.TypedInformerFactory{
+		// Unless told to cache only the labeled Addressables, every
+		// Addressable of the kind is listed and watched.
+		selector := labels.Everything()
+		if cfg.LabeledOnly {
+			selector = labeledSelector()
+		}
+		addressinformer := &reconciler.SelectedInformerFactory{
 			Client:       dynamicclient.Get(ctx),
 			Type:         &duckv1.AddressableType{},
+			Selector:     selector,
+			Namespace:    injection.GetNamespaceScope(ctx),
 			ResyncPeriod: 10 * time.Hour,
 			StopChannel:  ctx.Done(),
 		}
@@ -41,24 +82,232 @@ func NewControllerConstructor(name string, gvr schema.GroupVersionResource, info
 			panic(err)
 		}
 
+		stats := metrics.NewStatsReporter()
+		dryRun := dryrun.Reporter{
+			Resource: gvr.String(),
+			Stats:    stats,
+			Recorder: controller.GetEventRecorder(ctx),
+		}
+
+		// The Addressables missing from the informer are looked up from the
+		// API server when only the labeled ones are cached.
+		var addressableClient dynamic.NamespaceableResourceInterface
+		if cfg.LabeledOnly {
+			addressableClient = dynamicclient.Get(ctx).Resource(gvr)
+		}
+
 		c := &Reconciler{
 			eventingClientSet: eventingclient.Get(ctx),
 			triggerLister:     triggerInformer.Lister(),
+			triggerIndexer:    triggerInformer.Informer().GetIndexer(),
 			addressableLister: addressLister,
 			gvr:               gvr,
+			gk:                gk,
 			info:              info,
+			profileLister:     profileInformer.Lister(),
+			bindingLister:     bindingInformer.Lister(),
+			policyLister:      policyInformer.Lister(),
+			namespaceLister:   namespaceInformer.Lister(),
+			scope:             scope.FromContext(ctx),
+			recorder:          controller.GetEventRecorder(ctx),
+			configStore:       configStore,
+			stats:             stats,
+			addressableClient: addressableClient,
+			eventTypes: &eventtypes.Reconciler{
+				EventingClientSet: eventingclient.Get(ctx),
+				EventTypeLister:   eventTypeInformer.Lister(),
+				DryRun:            dryRun,
+			},
+			pipelines: &pipeline.Membership{
+				EventingClientSet: eventingclient.Get(ctx),
+				SequenceLister:    sequenceinformer.Get(ctx).Lister(),
+				ParallelLister:    parallelinformer.Get(ctx).Lister(),
+				DryRun:            dryRun,
+			},
 		}
 		impl := controller.NewImpl(c, logger, name)
 
+		// Each Addressable kind gets its own rate limiter, so that a burst of
+		// one kind does not hold back the others. controller.NewImpl takes no
+		// rate limiter, so its queue is swapped for one with ours before any
+		// handler can enqueue to it or the controller runs. Like LabeledOnly,
+		// this is only read here: a change to cfg.WorkQueue takes a restart
+		// of the controller to apply, unlike cfg.Writes.
+		impl.WorkQueue.ShutDown()
+		wq := cfg.WorkQueue
+		impl.WorkQueue = workqueue.NewNamedRateLimitingQueue(ratelimit.NewWorkQueueRateLimiter(wq.BaseDelay, wq.MaxDelay, wq.QPS, wq.Burst, gvr.String(), stats), name)
+
 		logger.Info("Setting up event handlers for %s", name)
 
 		addressInformer.AddEventHandler(controller.HandleAll(impl.Enqueue))
 
-		triggerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
-			FilterFunc: controller.Filter(eventingv1alpha1.SchemeGroupVersion.WithKind("Trigger")),
-			Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+		// Look again at the Addressables referencing a profile when it changes.
+		profileInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+			enqueueProfileReferences(impl, addressLister, obj)
+			if addressableClient != nil {
+				if profile, err := kmeta.DeletionHandlingAccessor(obj); err == nil {
+					enqueueTriggerOwners(impl, triggerInformer.Lister(), gk, profile.GetNamespace())
+				}
+			}
+		}))
+
+		// Look again at the Addressables in the namespace of a binding when it
+		// changes, both the ones it selects now and the ones it used to.
+		bindingInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+			binding, err := kmeta.DeletionHandlingAccessor(obj)
+			if err != nil {
+				return
+			}
+			impl.FilteredGlobalResync(func(obj interface{}) bool {
+				object, err := kmeta.DeletionHandlingAccessor(obj)
+				return err == nil && object.GetNamespace() == binding.GetNamespace()
+			}, addressInformer)
+			if addressableClient != nil {
+				// The Addressables a binding selects may not have the label,
+				// so they are listed from the API server, and the ones it
+				// used to select are found through their Triggers.
+				enqueueBindingSubjects(impl, addressableClient, gk, obj)
+				enqueueTriggerOwners(impl, triggerInformer.Lister(), gk, binding.GetNamespace())
+			}
+		}))
+
+		// Look again at the Addressables of a namespace when it opts in or
+		// out, or its default filter changes, so that their Triggers are
+		// made or cleaned up.
+		namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) {
+				oldNS, ok := old.(*corev1.Namespace)
+				if !ok {
+					return
+				}
+				newNS, ok := new.(*corev1.Namespace)
+				if !ok || (resources.NamespaceEnabled(oldNS) == resources.NamespaceEnabled(newNS) &&
+					oldNS.Annotations[resources.DefaultFilterAnnotation] == newNS.Annotations[resources.DefaultFilterAnnotation] &&
+					oldNS.Annotations[resources.DefaultFilterKindsAnnotation] == newNS.Annotations[resources.DefaultFilterKindsAnnotation]) {
+					return
+				}
+				impl.FilteredGlobalResync(func(obj interface{}) bool {
+					object, err := kmeta.DeletionHandlingAccessor(obj)
+					return err == nil && object.GetNamespace() == newNS.Name
+				}, addressInformer)
+				if addressableClient != nil {
+					enqueueTriggerOwners(impl, triggerInformer.Lister(), gk, newNS.Name)
+				}
+			},
 		})
 
+		// Look again at every Addressable when the namespace opt-in is
+		// turned on or off, so that the Triggers of the namespaces that have
+		// not opted in are cleaned up, or made again.
+		if configStore != nil {
+			optIn := cfg.NamespaceOptIn
+			configStore.Watch(ctx, func(at *config.AutoTrigger) {
+				if at.NamespaceOptIn == optIn {
+					return
+				}
+				optIn = at.NamespaceOptIn
+				impl.GlobalResync(addressInformer)
+				if addressableClient != nil {
+					enqueueTriggerOwners(impl, triggerInformer.Lister(), gk, metav1.NamespaceAll)
+				}
+			})
+		}
+
+		// Policies can allow or deny any Addressable, so look at them all again.
+		policyInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+			impl.GlobalResync(addressInformer)
+		}))
+
+		// Look again at the Addressable when one of its EventTypes is changed
+		// or deleted.
+		eventTypeInformer.Informer().AddEventHandler(eventtypes.Handler(impl, gk))
+
+		// Look again at the Addressable when one of its Triggers is changed or
+		// deleted, so that edits by hand are reverted.
+		triggerInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+			trigger, err := kmeta.DeletionHandlingAccessor(obj)
+			if err != nil {
+				return
+			}
+			if owner, key, ok := resources.Owner(trigger); ok && owner == gk {
+				impl.EnqueueKey(key)
+			}
+		}))
+
 		return impl
 	}
 }
+
+func enqueueProfileReferences(impl *controller.Impl, addressLister cache.GenericLister, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	profile, ok := obj.(*autotriggerv1alpha1.AutoTriggerProfile)
+	if !ok {
+		return
+	}
+	addressables, err := addressLister.ByNamespace(profile.Namespace).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, obj := range addressables {
+		addressable, ok := obj.(*duckv1.AddressableType)
+		if !ok {
+			continue
+		}
+		for _, name := range resources.ProfileNames(addressable) {
+			if name == profile.Name {
+				impl.Enqueue(addressable)
+				break
+			}
+		}
+	}
+}
+
+// enqueueBindingSubjects enqueues the Addressables of the kind the binding
+// selects, listed from the API server.
+func enqueueBindingSubjects(impl *controller.Impl, client dynamic.NamespaceableResourceInterface, gk schema.GroupKind, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*autotriggerv1alpha1.AutoTriggerBinding)
+	if !ok || schema.FromAPIVersionAndKind(binding.Spec.Subject.APIVersion, binding.Spec.Subject.Kind).GroupKind() != gk {
+		return
+	}
+	selector, err := resources.BindingSelector(binding)
+	if err != nil {
+		return
+	}
+	list, err := client.Namespace(binding.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return
+	}
+	for i := range list.Items {
+		impl.Enqueue(&list.Items[i])
+	}
+}
+
+// enqueueTriggerOwners enqueues the Addressables of the kind in the namespace
+// that have Triggers, in every namespace for metav1.NamespaceAll.
+func enqueueTriggerOwners(impl *controller.Impl, triggerLister eventinglisters.TriggerLister, gk schema.GroupKind, namespace string) {
+	triggers, err := triggerLister.List(labels.SelectorFromSet(resources.MakeManagedSelector()))
+	if err != nil {
+		return
+	}
+	for _, trigger := range triggers {
+		if owner, key, ok := resources.Owner(trigger); ok && owner == gk && (namespace == metav1.NamespaceAll || key.Namespace == namespace) {
+			impl.EnqueueKey(key)
+		}
+	}
+}
+
+// labeledSelector selects the Addressables with the autotrigger label, set to
+// any value. Turning autotrigger off by setting the label to "false" keeps the
+// Addressable in the informer, and removing the label is seen as a delete.
+func labeledSelector() labels.Selector {
+	req, err := labels.NewRequirement(resources.AutoTriggerLabel, selection.Exists, nil)
+	if err != nil {
+		panic(err)
+	}
+	return labels.NewSelector().Add(*req)
+}
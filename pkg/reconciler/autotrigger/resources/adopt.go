@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/apis/v1alpha1"
+)
+
+// Adoptable reports whether a Trigger written by hand does what the desired
+// Trigger would, so that it can be taken over instead of duplicated: it has no
+// controller and no owner, is on the same Broker in the same namespace, has
+// the same filter, and delivers to the same subscriber.
+func Adoptable(trigger, desired *eventingv1alpha1.Trigger) bool {
+	if metav1.GetControllerOf(trigger) != nil {
+		return false
+	}
+	if _, _, ok := Owner(trigger); ok {
+		return false
+	}
+	return trigger.Namespace == desired.Namespace &&
+		trigger.Spec.Broker == desired.Spec.Broker &&
+		equality.Semantic.DeepEqual(filterAttributes(trigger), filterAttributes(desired)) &&
+		sameSubscriber(trigger, desired)
+}
+
+func filterAttributes(trigger *eventingv1alpha1.Trigger) map[string]string {
+	if trigger.Spec.Filter == nil || trigger.Spec.Filter.Attributes == nil || len(*trigger.Spec.Filter.Attributes) == 0 {
+		return nil
+	}
+	return *trigger.Spec.Filter.Attributes
+}
+
+// sameSubscriber compares the subscribers of the Triggers. References are
+// compared by group, kind, name and namespace, as hand-written Triggers may
+// use another version, spell out the namespace, or use the deprecated inline
+// reference fields.
+func sameSubscriber(trigger, desired *eventingv1alpha1.Trigger) bool {
+	got, want := trigger.Spec.Subscriber, desired.Spec.Subscriber
+	if got == nil || want == nil {
+		return got == want
+	}
+	if !equality.Semantic.DeepEqual(got.URI, want.URI) {
+		return false
+	}
+	gotRef, wantRef := subscriberRef(got, trigger.Namespace), subscriberRef(want, desired.Namespace)
+	if gotRef == nil || wantRef == nil {
+		return gotRef == wantRef
+	}
+	return schema.FromAPIVersionAndKind(gotRef.APIVersion, gotRef.Kind).GroupKind() == schema.FromAPIVersionAndKind(wantRef.APIVersion, wantRef.Kind).GroupKind() &&
+		gotRef.Name == wantRef.Name &&
+		gotRef.Namespace == wantRef.Namespace
+}
+
+// subscriberRef returns the reference of the Destination, defaulting its
+// namespace to the one of the Trigger.
+func subscriberRef(d *v1alpha1.Destination, namespace string) *corev1.ObjectReference {
+	var ref *corev1.ObjectReference
+	if d.Ref != nil {
+		ref = d.Ref.DeepCopy()
+	} else if d.DeprecatedKind != "" {
+		ref = &corev1.ObjectReference{
+			APIVersion: d.DeprecatedAPIVersion,
+			Kind:       d.DeprecatedKind,
+			Name:       d.DeprecatedName,
+			Namespace:  d.DeprecatedNamespace,
+		}
+	} else {
+		return nil
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+	return ref
+}
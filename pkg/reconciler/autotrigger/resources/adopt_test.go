@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestAdoptable(t *testing.T) {
+	subscribed := func(t *eventingv1alpha1.Trigger, d *v1alpha1.Destination) *eventingv1alpha1.Trigger {
+		t.Spec.Subscriber = d
+		return t
+	}
+	ref := func(apiVersion, namespace string) *v1alpha1.Destination {
+		return &v1alpha1.Destination{Ref: &corev1.ObjectReference{
+			APIVersion: apiVersion,
+			Kind:       "Service",
+			Namespace:  namespace,
+			Name:       "hello",
+		}}
+	}
+	desired := subscribed(trigger("tenant", "default", "dev.example.ping"), ref("serving.knative.dev/v1", ""))
+
+	tests := map[string]struct {
+		trigger *eventingv1alpha1.Trigger
+		want    bool
+	}{
+		"same": {
+			trigger: subscribed(trigger("tenant", "default", "dev.example.ping"), ref("serving.knative.dev/v1", "")),
+			want:    true,
+		},
+		"other version and explicit namespace": {
+			trigger: subscribed(trigger("tenant", "default", "dev.example.ping"), ref("serving.knative.dev/v1alpha1", "tenant")),
+			want:    true,
+		},
+		"deprecated reference": {
+			trigger: subscribed(trigger("tenant", "default", "dev.example.ping"), &v1alpha1.Destination{
+				DeprecatedAPIVersion: "serving.knative.dev/v1",
+				DeprecatedKind:       "Service",
+				DeprecatedName:       "hello",
+			}),
+			want: true,
+		},
+		"other subscriber": {
+			trigger: subscribed(trigger("tenant", "default", "dev.example.ping"), ref("serving.knative.dev/v1", "other")),
+		},
+		"other broker": {
+			trigger: subscribed(trigger("tenant", "other", "dev.example.ping"), ref("serving.knative.dev/v1", "")),
+		},
+		"other filter": {
+			trigger: subscribed(trigger("tenant", "default", "dev.example.pong"), ref("serving.knative.dev/v1", "")),
+		},
+		"controlled": {
+			trigger: func() *eventingv1alpha1.Trigger {
+				t := subscribed(trigger("tenant", "default", "dev.example.ping"), ref("serving.knative.dev/v1", ""))
+				t.OwnerReferences = []metav1.OwnerReference{{Name: "other", Controller: ptr.Bool(true)}}
+				return t
+			}(),
+		},
+		"owned by another Addressable": {
+			trigger: func() *eventingv1alpha1.Trigger {
+				t := subscribed(trigger("tenant", "default", "dev.example.ping"), ref("serving.knative.dev/v1", ""))
+				t.Labels = map[string]string{ownerKindLabel: "Service.serving.knative.dev"}
+				return t
+			}(),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Adoptable(tc.trigger, desired); got != tc.want {
+				t.Errorf("Adoptable() = %v, wanted %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+)
+
+// BindingSelector returns the label selector of the AutoTriggerBinding
+// subject. A nil selector selects everything.
+func BindingSelector(binding *autotriggerv1alpha1.AutoTriggerBinding) (labels.Selector, error) {
+	if binding.Spec.Subject.Selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(binding.Spec.Subject.Selector)
+}
+
+// BindingMatches reports whether the AutoTriggerBinding selects the
+// Addressable. Only the group of the subject's APIVersion is compared, so a
+// binding keeps matching when the Addressable is served at another version.
+func BindingMatches(binding *autotriggerv1alpha1.AutoTriggerBinding, addressable *duckv1.AddressableType) (bool, error) {
+	subject := schema.FromAPIVersionAndKind(binding.Spec.Subject.APIVersion, binding.Spec.Subject.Kind)
+	if binding.Namespace != addressable.Namespace ||
+		subject.GroupKind() != addressable.GroupVersionKind().GroupKind() {
+		return false, nil
+	}
+	selector, err := BindingSelector(binding)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(addressable.Labels)), nil
+}
+
+// BindingFilters returns the filter entries the AutoTriggerBinding applies.
+// A binding without filters subscribes to every event on the default Broker,
+// like an empty filter annotation.
+func BindingFilters(binding *autotriggerv1alpha1.AutoTriggerBinding) []autotrigger.Filter {
+	if len(binding.Spec.Filters) == 0 {
+		return []autotrigger.Filter{{}}
+	}
+	return binding.Spec.Filters
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+)
+
+func TestBindingMatches(t *testing.T) {
+	addressable := &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "foo"},
+		},
+	}
+
+	tests := map[string]struct {
+		namespace string
+		subject   autotriggerv1alpha1.BindingSubject
+		want      bool
+	}{
+		"nil selector": {
+			namespace: "default",
+			subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+			},
+			want: true,
+		},
+		"matching labels": {
+			namespace: "default",
+			subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+			},
+			want: true,
+		},
+		"other labels": {
+			namespace: "default",
+			subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}},
+			},
+		},
+		"other version": {
+			namespace: "default",
+			subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.knative.dev/v1beta1",
+				Kind:       "Service",
+			},
+			want: true,
+		},
+		"other group": {
+			namespace: "default",
+			subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.example.dev/v1",
+				Kind:       "Service",
+			},
+		},
+		"other kind": {
+			namespace: "default",
+			subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Route",
+			},
+		},
+		"other namespace": {
+			namespace: "events",
+			subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: "serving.knative.dev/v1",
+				Kind:       "Service",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			binding := &autotriggerv1alpha1.AutoTriggerBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "binding", Namespace: tc.namespace},
+				Spec:       autotriggerv1alpha1.AutoTriggerBindingSpec{Subject: tc.subject},
+			}
+			got, err := BindingMatches(binding, addressable)
+			if err != nil {
+				t.Fatalf("BindingMatches() = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("BindingMatches() = %v, wanted %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -18,29 +18,147 @@ package resources
 import (
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
 )
 
 const (
-	autoTriggerLabel = "eventing.knative.dev/autotrigger"
+	// AutoTriggerLabel turns autotrigger on for an Addressable when "true".
+	AutoTriggerLabel = autotrigger.Label
+
+	// NamespaceEnabledLabel lets autotrigger act in a Namespace when "true",
+	// if the namespace opt-in is configured.
+	NamespaceEnabledLabel = "eventing.knative.dev/autotrigger-enabled"
+
+	ownerNamespaceLabel = autotrigger.OwnerNamespaceLabel
+	ownerNameLabel      = autotrigger.OwnerNameLabel
+	ownerKindLabel      = autotrigger.OwnerKindLabel
+	ownerUIDLabel       = autotrigger.OwnerUIDLabel
+
+	// OwnerUIDIndex is the name of the Trigger informer index keyed on the
+	// UID of the Addressable the Trigger was made for.
+	OwnerUIDIndex = "autotrigger-owner-uid"
 )
 
+// AutoTriggerEnabled reports whether the AutoTriggerLabel of the Addressable
+// turns autotrigger on.
 func AutoTriggerEnabled(a *duckv1.AddressableType) bool {
-	if enabled, ok := a.Labels[autoTriggerLabel]; ok {
-		if strings.EqualFold(enabled, "true") {
-			return true
-		}
+	return autotrigger.Enabled(a)
+}
+
+// NamespaceEnabled reports whether the Namespace opted in to autotrigger.
+func NamespaceEnabled(ns *corev1.Namespace) bool {
+	return strings.EqualFold(ns.Labels[NamespaceEnabledLabel], "true")
+}
+
+// MakeLabels constructs the labels we will apply to Trigger resources: the
+// labels of the Addressable selected by the configuration, and the
+// managed-by label.
+func MakeLabels(a *duckv1.AddressableType, cfg *config.AutoTrigger) map[string]string {
+	labels := cfg.Labels.Filter(a.ObjectMeta.Labels)
+	labels[config.ManagedByLabel] = config.ManagedBy
+	return labels
+}
+
+// MakeAnnotations constructs the annotations we will apply to Trigger
+// resources, selected from the annotations of the Addressable by the
+// configuration.
+func MakeAnnotations(a *duckv1.AddressableType, cfg *config.AutoTrigger) map[string]string {
+	annotations := cfg.Annotations.Filter(a.ObjectMeta.Annotations)
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// MakeManagedSelector constructs the labels that select the Triggers made by
+// autotrigger.
+func MakeManagedSelector() map[string]string {
+	return map[string]string{
+		config.ManagedByLabel: config.ManagedBy,
+	}
+}
+
+// MakeOwnerSelector constructs the labels that select the Triggers created for
+// the named Addressable of the given kind.
+func MakeOwnerSelector(namespace, name string, gk schema.GroupKind) map[string]string {
+	return autotrigger.OwnerSelector(namespace, name, gk)
+}
+
+// MakeOwnerLabels constructs the labels that tie a Trigger to its
+// Addressable.
+func MakeOwnerLabels(a *duckv1.AddressableType) map[string]string {
+	return autotrigger.OwnerLabels(a)
+}
+
+// Managed reports whether the Trigger carries the managed-by label of the
+// Triggers made by autotrigger.
+func Managed(trigger metav1.Object) bool {
+	return trigger.GetLabels()[config.ManagedByLabel] == config.ManagedBy
+}
+
+// OwnerNamespace returns the namespace of the Addressable a Trigger was
+// created for.
+func OwnerNamespace(trigger metav1.Object) string {
+	if namespace, ok := trigger.GetLabels()[ownerNamespaceLabel]; ok {
+		return namespace
+	}
+	return trigger.GetNamespace()
+}
+
+// Owner returns the kind, namespace and name of the Addressable a Trigger was
+// made for.
+func Owner(trigger metav1.Object) (schema.GroupKind, types.NamespacedName, bool) {
+	labels := trigger.GetLabels()
+	if kind, ok := labels[ownerKindLabel]; ok {
+		return schema.ParseGroupKind(kind), types.NamespacedName{
+			Namespace: labels[ownerNamespaceLabel],
+			Name:      labels[ownerNameLabel],
+		}, true
 	}
-	return false
+	// Triggers made before the owner labels existed are found through their
+	// controller reference. Other controllers, like the one for pipelines,
+	// control Triggers too, so only the ones autotrigger made count.
+	if ref := metav1.GetControllerOf(trigger); ref != nil && Managed(trigger) {
+		return schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind(), types.NamespacedName{
+			Namespace: trigger.GetNamespace(),
+			Name:      ref.Name,
+		}, true
+	}
+	return schema.GroupKind{}, types.NamespacedName{}, false
 }
 
-// MakeLabels constructs the labels we will apply to Trigger resources.
-func MakeLabels(a *duckv1.AddressableType) map[string]string {
-	labels := make(map[string]string, len(a.ObjectMeta.Labels))
+// OwnerUIDIndexFunc indexes Triggers on the UID of the Addressable they were
+// made for.
+func OwnerUIDIndexFunc(obj interface{}) ([]string, error) {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if uid, ok := object.GetLabels()[ownerUIDLabel]; ok {
+		return []string{uid}, nil
+	}
+	// Triggers made before the owner labels existed are found through their
+	// controller reference, like in Owner.
+	if ref := metav1.GetControllerOf(object); ref != nil && Managed(object) {
+		return []string{string(ref.UID)}, nil
+	}
+	return nil, nil
+}
 
-	// Pass through the labels on the Service to child resources.
-	for k, v := range a.ObjectMeta.Labels {
-		labels[k] = v
+// AddOwnerUIDIndex adds the OwnerUIDIndex to a Trigger informer that does not
+// have it yet. It has to be called before the informer is started.
+func AddOwnerUIDIndex(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[OwnerUIDIndex]; ok {
+		return nil
 	}
-	return labels
+	return informer.AddIndexers(cache.Indexers{OwnerUIDIndex: OwnerUIDIndexFunc})
 }
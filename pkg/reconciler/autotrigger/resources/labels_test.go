@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestOwnerUIDIndexFunc(t *testing.T) {
+	tests := map[string]struct {
+		meta metav1.ObjectMeta
+		want []string
+	}{
+		"owner label": {
+			meta: metav1.ObjectMeta{
+				Labels: map[string]string{ownerUIDLabel: "abc-123"},
+			},
+			want: []string{"abc-123"},
+		},
+		"controller reference": {
+			meta: metav1.ObjectMeta{
+				Labels: MakeManagedSelector(),
+				OwnerReferences: []metav1.OwnerReference{{
+					UID:        "abc-123",
+					Controller: ptr.Bool(true),
+				}},
+			},
+			want: []string{"abc-123"},
+		},
+		"controller reference, not made by autotrigger": {
+			meta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{
+					UID:        "abc-123",
+					Controller: ptr.Bool(true),
+				}},
+			},
+		},
+		"no owner": {
+			meta: metav1.ObjectMeta{
+				OwnerReferences: []metav1.OwnerReference{{
+					UID: "abc-123",
+				}},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := OwnerUIDIndexFunc(&eventingv1alpha1.Trigger{ObjectMeta: tc.meta})
+			if err != nil {
+				t.Fatalf("OwnerUIDIndexFunc() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("OwnerUIDIndexFunc() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestOwner(t *testing.T) {
+	tests := map[string]struct {
+		meta     metav1.ObjectMeta
+		wantKind schema.GroupKind
+		wantKey  types.NamespacedName
+		wantOK   bool
+	}{
+		"owner labels": {
+			meta: metav1.ObjectMeta{
+				Namespace: "events",
+				Labels: MakeOwnerSelector("default", "foo", schema.GroupKind{
+					Group: "serving.knative.dev",
+					Kind:  "Service",
+				}),
+			},
+			wantKind: schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"},
+			wantKey:  types.NamespacedName{Namespace: "default", Name: "foo"},
+			wantOK:   true,
+		},
+		"controller reference": {
+			meta: metav1.ObjectMeta{
+				Namespace: "default",
+				Labels:    MakeManagedSelector(),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "serving.knative.dev/v1",
+					Kind:       "Service",
+					Name:       "foo",
+					Controller: ptr.Bool(true),
+				}},
+			},
+			wantKind: schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"},
+			wantKey:  types.NamespacedName{Namespace: "default", Name: "foo"},
+			wantOK:   true,
+		},
+		"controller reference, not made by autotrigger": {
+			meta: metav1.ObjectMeta{
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "flows.knative.dev/v1alpha1",
+					Kind:       "Sequence",
+					Name:       "foo",
+					Controller: ptr.Bool(true),
+				}},
+			},
+		},
+		"no owner": {
+			meta: metav1.ObjectMeta{Namespace: "default"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kind, key, ok := Owner(&eventingv1alpha1.Trigger{ObjectMeta: tc.meta})
+			if kind != tc.wantKind || key != tc.wantKey || ok != tc.wantOK {
+				t.Errorf("Owner() = %v, %v, %v, wanted %v, %v, %v", kind, key, ok, tc.wantKind, tc.wantKey, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestNamespaceEnabled(t *testing.T) {
+	tests := map[string]struct {
+		labels map[string]string
+		want   bool
+	}{
+		"no label": {},
+		"enabled": {
+			labels: map[string]string{NamespaceEnabledLabel: "True"},
+			want:   true,
+		},
+		"disabled": {
+			labels: map[string]string{NamespaceEnabledLabel: "false"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: tc.labels}}
+			if got := NamespaceEnabled(ns); got != tc.want {
+				t.Errorf("NamespaceEnabled() = %v, wanted %v", got, tc.want)
+			}
+		})
+	}
+}
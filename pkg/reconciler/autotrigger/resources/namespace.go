@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+)
+
+const (
+	// DefaultFilterAnnotation holds, on a Namespace, the JSON list of filter
+	// entries applied to every Addressable in it, in addition to their own.
+	DefaultFilterAnnotation = "trigger.eventing.knative.dev/default-filter"
+	// DefaultFilterKindsAnnotation holds, on a Namespace, a comma separated
+	// list of the kinds, as Kind.group, the default filter applies to. It
+	// applies to every kind when unset.
+	DefaultFilterKindsAnnotation = "trigger.eventing.knative.dev/default-filter-kinds"
+	// InheritDefaultFilterAnnotation set to "false" on an Addressable opts it
+	// out of the default filter of its namespace.
+	InheritDefaultFilterAnnotation = "trigger.eventing.knative.dev/inherit-default-filter"
+)
+
+// brokerKind never gets the default filter, as a Broker subscribed to itself
+// would deliver its events back to itself.
+var brokerKind = schema.GroupKind{Group: "eventing.knative.dev", Kind: "Broker"}
+
+// NamespaceDefaultFilters returns the entries of the default filter of the
+// Namespace that apply to the Addressable, of the given kind. Addressables
+// opt out with the InheritDefaultFilterAnnotation, or by setting the
+// autotrigger label to "false".
+func NamespaceDefaultFilters(ns *corev1.Namespace, gk schema.GroupKind, addressable *duckv1.AddressableType) ([]autotrigger.Filter, error) {
+	raw, ok := ns.Annotations[DefaultFilterAnnotation]
+	if !ok || gk == brokerKind {
+		return nil, nil
+	}
+	if strings.EqualFold(addressable.Labels[AutoTriggerLabel], "false") ||
+		strings.EqualFold(addressable.Annotations[InheritDefaultFilterAnnotation], "false") {
+		return nil, nil
+	}
+	if kinds, ok := ns.Annotations[DefaultFilterKindsAnnotation]; ok && !listsKind(kinds, gk) {
+		return nil, nil
+	}
+	return ParseDefaultFilter(raw)
+}
+
+// ParseDefaultFilter parses and validates the value of the
+// DefaultFilterAnnotation with autotrigger.ParseFilters. Like the filter
+// annotation of an Addressable, an empty list subscribes to every event on the
+// default Broker.
+func ParseDefaultFilter(raw string) ([]autotrigger.Filter, error) {
+	filters, err := autotrigger.ParseFilters(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation of the namespace: %v", DefaultFilterAnnotation, err)
+	}
+	return filters, nil
+}
+
+func listsKind(kinds string, gk schema.GroupKind) bool {
+	for _, kind := range strings.Split(kinds, ",") {
+		if kind = strings.TrimSpace(kind); kind != "" && schema.ParseGroupKind(kind) == gk {
+			return true
+		}
+	}
+	return false
+}
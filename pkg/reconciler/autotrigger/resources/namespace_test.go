@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+)
+
+func TestNamespaceDefaultFilters(t *testing.T) {
+	service := schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"}
+	channel := schema.GroupKind{Group: "messaging.knative.dev", Kind: "Channel"}
+
+	tests := map[string]struct {
+		annotations map[string]string
+		gk          schema.GroupKind
+		meta        metav1.ObjectMeta
+		want        []autotrigger.Filter
+		wantErr     bool
+	}{
+		"no default": {
+			gk: service,
+		},
+		"default": {
+			annotations: map[string]string{DefaultFilterAnnotation: `[{"type":"dev.example.audit"}]`},
+			gk:          service,
+			want:        []autotrigger.Filter{{Type: "dev.example.audit"}},
+		},
+		"every event": {
+			annotations: map[string]string{DefaultFilterAnnotation: ""},
+			gk:          service,
+			want:        []autotrigger.Filter{{}},
+		},
+		"listed kind": {
+			annotations: map[string]string{
+				DefaultFilterAnnotation:      `[{"type":"dev.example.audit"}]`,
+				DefaultFilterKindsAnnotation: "Channel.messaging.knative.dev, Service.serving.knative.dev",
+			},
+			gk:   service,
+			want: []autotrigger.Filter{{Type: "dev.example.audit"}},
+		},
+		"other kind": {
+			annotations: map[string]string{
+				DefaultFilterAnnotation:      `[{"type":"dev.example.audit"}]`,
+				DefaultFilterKindsAnnotation: "Service.serving.knative.dev",
+			},
+			gk: channel,
+		},
+		"broker": {
+			annotations: map[string]string{DefaultFilterAnnotation: `[{"type":"dev.example.audit"}]`},
+			gk:          schema.GroupKind{Group: "eventing.knative.dev", Kind: "Broker"},
+		},
+		"opted out": {
+			annotations: map[string]string{DefaultFilterAnnotation: `[{"type":"dev.example.audit"}]`},
+			gk:          service,
+			meta:        metav1.ObjectMeta{Annotations: map[string]string{InheritDefaultFilterAnnotation: "false"}},
+		},
+		"autotrigger off": {
+			annotations: map[string]string{DefaultFilterAnnotation: `[{"type":"dev.example.audit"}]`},
+			gk:          service,
+			meta:        metav1.ObjectMeta{Labels: map[string]string{AutoTriggerLabel: "false"}},
+		},
+		"invalid": {
+			annotations: map[string]string{DefaultFilterAnnotation: `{"type"`},
+			gk:          service,
+			wantErr:     true,
+		},
+		"unknown key": {
+			annotations: map[string]string{DefaultFilterAnnotation: `[{"tpye":"dev.example.audit"}]`},
+			gk:          service,
+			wantErr:     true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: tc.annotations}}
+			got, err := NamespaceDefaultFilters(ns, tc.gk, &duckv1.AddressableType{ObjectMeta: tc.meta})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NamespaceDefaultFilters() = %v, wanted error %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(autotrigger.Filter{})); diff != "" {
+				t.Errorf("NamespaceDefaultFilters() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
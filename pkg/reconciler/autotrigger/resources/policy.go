@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+
+	"github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+)
+
+// Denial explains why a Trigger was not allowed by a policy.
+type Denial struct {
+	Trigger *eventingv1alpha1.Trigger
+	Policy  string
+	Reason  string
+}
+
+func (d Denial) String() string {
+	return fmt.Sprintf("Trigger to Broker %s/%s denied by AutoTriggerPolicy %q: %s",
+		d.Trigger.Namespace, d.Trigger.Spec.Broker, d.Policy, d.Reason)
+}
+
+// InvalidPolicy is an AutoTriggerPolicy left out because its
+// namespaceSelector cannot be parsed.
+type InvalidPolicy struct {
+	Policy *v1alpha1.AutoTriggerPolicy
+	Err    error
+}
+
+func (p InvalidPolicy) String() string {
+	return fmt.Sprintf("AutoTriggerPolicy %q ignored: invalid namespaceSelector: %v", p.Policy.Name, p.Err)
+}
+
+// ApplyPolicies returns the Triggers of the Addressable in namespace that are
+// allowed by every policy selecting that namespace, and the denials of the
+// others. A policy with an invalid namespaceSelector is left out and returned
+// in invalid, rather than holding back the Triggers of every namespace.
+func ApplyPolicies(policies []*v1alpha1.AutoTriggerPolicy, namespace *corev1.Namespace, triggers []*eventingv1alpha1.Trigger) ([]*eventingv1alpha1.Trigger, []Denial, []InvalidPolicy) {
+	var denials []Denial
+	var invalid []InvalidPolicy
+	for _, policy := range policies {
+		selected, err := selectsNamespace(policy, namespace)
+		if err != nil {
+			invalid = append(invalid, InvalidPolicy{Policy: policy, Err: err})
+			continue
+		} else if !selected {
+			continue
+		}
+
+		kept := make([]*eventingv1alpha1.Trigger, 0, len(triggers))
+		for _, t := range triggers {
+			if reason := deniedBy(policy, namespace.Name, t); reason != "" {
+				denials = append(denials, Denial{Trigger: t, Policy: policy.Name, Reason: reason})
+				continue
+			}
+			if max := policy.Spec.MaxTriggers; max != nil && len(kept) >= int(*max) {
+				denials = append(denials, Denial{Trigger: t, Policy: policy.Name, Reason: fmt.Sprintf("more than %d Triggers", *max)})
+				continue
+			}
+			kept = append(kept, t)
+		}
+		triggers = kept
+	}
+	return triggers, denials, invalid
+}
+
+func selectsNamespace(policy *v1alpha1.AutoTriggerPolicy, namespace *corev1.Namespace) (bool, error) {
+	if policy.Spec.NamespaceSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespace.Labels)), nil
+}
+
+// deniedBy returns the reason policy denies the Trigger, or "" if it allows it.
+func deniedBy(policy *v1alpha1.AutoTriggerPolicy, namespace string, t *eventingv1alpha1.Trigger) string {
+	if len(policy.Spec.AllowedBrokers) > 0 {
+		broker := t.Spec.Broker
+		if t.Namespace != namespace {
+			broker = t.Namespace + "/" + broker
+		}
+		if !matchesAny(policy.Spec.AllowedBrokers, broker) {
+			return fmt.Sprintf("broker %q is not allowed", broker)
+		}
+	}
+
+	eventType := triggerType(t)
+	if len(policy.Spec.AllowedTypes) > 0 && !matchesAny(policy.Spec.AllowedTypes, eventType) {
+		if eventType == "" {
+			return "subscribing to any type is not allowed"
+		}
+		return fmt.Sprintf("type %q is not allowed", eventType)
+	}
+	if len(policy.Spec.DeniedTypes) > 0 {
+		if eventType == "" {
+			return "subscribing to any type is not allowed while types are denied"
+		}
+		if matchesAny(policy.Spec.DeniedTypes, eventType) {
+			return fmt.Sprintf("type %q is denied", eventType)
+		}
+	}
+	return ""
+}
+
+func triggerType(t *eventingv1alpha1.Trigger) string {
+	if t.Spec.Filter == nil || t.Spec.Filter.Attributes == nil {
+		return ""
+	}
+	return (*t.Spec.Filter.Attributes)["type"]
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
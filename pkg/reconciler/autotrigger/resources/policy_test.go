@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+
+	"github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+)
+
+func trigger(namespace, broker, eventType string) *eventingv1alpha1.Trigger {
+	attributes := eventingv1alpha1.TriggerFilterAttributes{}
+	if eventType != "" {
+		attributes["type"] = eventType
+	}
+	return &eventingv1alpha1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		Spec: eventingv1alpha1.TriggerSpec{
+			Broker: broker,
+			Filter: &eventingv1alpha1.TriggerFilter{Attributes: &attributes},
+		},
+	}
+}
+
+func TestApplyPolicies(t *testing.T) {
+	max := int32(1)
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "tenant",
+			Labels: map[string]string{"team": "a"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		policy   v1alpha1.AutoTriggerPolicySpec
+		triggers []*eventingv1alpha1.Trigger
+		want     []string
+	}{{
+		name:     "no restrictions",
+		triggers: []*eventingv1alpha1.Trigger{trigger("tenant", "default", "")},
+		want:     []string{"default/"},
+	}, {
+		name: "namespace not selected",
+		policy: v1alpha1.AutoTriggerPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+			AllowedBrokers:    []string{"none"},
+		},
+		triggers: []*eventingv1alpha1.Trigger{trigger("tenant", "default", "")},
+		want:     []string{"default/"},
+	}, {
+		name: "allowed brokers",
+		policy: v1alpha1.AutoTriggerPolicySpec{
+			AllowedBrokers: []string{"default", "events/*"},
+		},
+		triggers: []*eventingv1alpha1.Trigger{
+			trigger("tenant", "default", "a"),
+			trigger("tenant", "other", "a"),
+			trigger("events", "shared", "a"),
+		},
+		want: []string{"default/a", "shared/a"},
+	}, {
+		name: "allowed and denied types",
+		policy: v1alpha1.AutoTriggerPolicySpec{
+			AllowedTypes: []string{"dev.example.*"},
+			DeniedTypes:  []string{"dev.example.secret"},
+		},
+		triggers: []*eventingv1alpha1.Trigger{
+			trigger("tenant", "default", "dev.example.order"),
+			trigger("tenant", "default", "dev.example.secret"),
+			trigger("tenant", "default", "com.other"),
+			trigger("tenant", "default", ""),
+		},
+		want: []string{"default/dev.example.order"},
+	}, {
+		name: "max triggers",
+		policy: v1alpha1.AutoTriggerPolicySpec{
+			MaxTriggers: &max,
+		},
+		triggers: []*eventingv1alpha1.Trigger{
+			trigger("tenant", "default", "a"),
+			trigger("tenant", "default", "b"),
+		},
+		want: []string{"default/a"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := &v1alpha1.AutoTriggerPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec:       test.policy,
+			}
+			allowed, denials, invalid := ApplyPolicies([]*v1alpha1.AutoTriggerPolicy{policy}, namespace, test.triggers)
+			if len(invalid) != 0 {
+				t.Fatalf("ApplyPolicies() = %v", invalid)
+			}
+			var got []string
+			for _, trigger := range allowed {
+				got = append(got, trigger.Spec.Broker+"/"+triggerType(trigger))
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("ApplyPolicies (-want, +got) = %v", diff)
+			}
+			if len(allowed)+len(denials) != len(test.triggers) {
+				t.Errorf("ApplyPolicies() allowed %d and denied %d of %d", len(allowed), len(denials), len(test.triggers))
+			}
+		})
+	}
+}
+
+func TestApplyPoliciesInvalidSelector(t *testing.T) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant"}}
+	invalidPolicy := &v1alpha1.AutoTriggerPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid"},
+		Spec: v1alpha1.AutoTriggerPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Near"}},
+			},
+			AllowedBrokers: []string{"none"},
+		},
+	}
+	typesPolicy := &v1alpha1.AutoTriggerPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "types"},
+		Spec: v1alpha1.AutoTriggerPolicySpec{
+			DeniedTypes: []string{"dev.example.secret"},
+		},
+	}
+	triggers := []*eventingv1alpha1.Trigger{
+		trigger("tenant", "default", "dev.example.order"),
+		trigger("tenant", "default", "dev.example.secret"),
+	}
+
+	// The invalid policy is left out, and the others still apply.
+	allowed, denials, invalid := ApplyPolicies([]*v1alpha1.AutoTriggerPolicy{invalidPolicy, typesPolicy}, namespace, triggers)
+	if len(invalid) != 1 || invalid[0].Policy != invalidPolicy || invalid[0].Err == nil {
+		t.Errorf("ApplyPolicies() invalid = %v, wanted the policy %q", invalid, invalidPolicy.Name)
+	}
+	if len(allowed) != 1 || triggerType(allowed[0]) != "dev.example.order" {
+		t.Errorf("ApplyPolicies() allowed = %v, wanted the Trigger of dev.example.order", allowed)
+	}
+	if len(denials) != 1 || denials[0].Policy != typesPolicy.Name {
+		t.Errorf("ApplyPolicies() denials = %v, wanted one by %q", denials, typesPolicy.Name)
+	}
+}
@@ -17,90 +17,44 @@ limitations under the License.
 package resources
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources/names"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+
 	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
-	"knative.dev/pkg/apis/v1alpha1"
-	"knative.dev/pkg/ptr"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
 )
 
 const (
-	filterAnnotation = "trigger.eventing.knative.dev/filter"
+	// FilterAnnotation holds the JSON list of filter entries of an
+	// Addressable, one Trigger per entry.
+	FilterAnnotation = autotrigger.FilterAnnotation
+	// ProfileAnnotation holds a comma separated list of the names of the
+	// AutoTriggerProfiles an Addressable uses.
+	ProfileAnnotation = autotrigger.ProfileAnnotation
+
+	// DefaultBroker is the Broker used when a filter does not name one.
+	DefaultBroker = autotrigger.DefaultBroker
 )
 
-type brokerFilters map[string]string
-
-func (bf brokerFilters) Broker() string {
-	if b, ok := bf["broker"]; ok {
-		return b
-	}
-	return "default"
-}
-
-func (bf brokerFilters) Filters() *eventingv1alpha1.TriggerFilterAttributes {
-	f := make(eventingv1alpha1.TriggerFilterAttributes, 0)
-	for k, v := range bf {
-		if k == "broker" {
-			continue
+// ProfileNames returns the names of the AutoTriggerProfiles referenced by the
+// profile annotation of the Addressable.
+func ProfileNames(addressable *duckv1.AddressableType) []string {
+	var names []string
+	for _, name := range strings.Split(addressable.Annotations[ProfileAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
 		}
-		f[k] = v
 	}
-	return &f
+	return names
 }
 
-// MakeTrigger creates a Trigger from a Service object.
-func MakeTriggers(addressable *duckv1.AddressableType) ([]*eventingv1alpha1.Trigger, error) {
-	rawFilter, ok := addressable.Annotations[filterAnnotation]
-	if !ok {
-		return []*eventingv1alpha1.Trigger(nil), nil
-	}
-
-	filters := make([]brokerFilters, 0)
-	if rawFilter == "" || rawFilter == "[{}]" || rawFilter == "[]" {
-		filters = append(filters, brokerFilters{})
-	} else if err := json.Unmarshal([]byte(rawFilter), &filters); err != nil {
-		return nil, fmt.Errorf("failed to extract auto-trigger from service: %s", err.Error())
-	}
-
-	triggers := make([]*eventingv1alpha1.Trigger, 0)
-
-	subscriber := &v1alpha1.Destination{
-		Ref: &corev1.ObjectReference{
-			APIVersion: addressable.APIVersion,
-			Kind:       addressable.Kind,
-			Name:       addressable.Name,
-		},
-	}
-
-	for _, filter := range filters {
-		t := &eventingv1alpha1.Trigger{
-			ObjectMeta: metav1.ObjectMeta{
-				GenerateName: names.Trigger(addressable) + "-",
-				Namespace:    addressable.Namespace,
-				OwnerReferences: []metav1.OwnerReference{{
-					APIVersion:         addressable.APIVersion,
-					Kind:               addressable.Kind,
-					Name:               addressable.Name,
-					UID:                addressable.UID,
-					BlockOwnerDeletion: ptr.Bool(true),
-					Controller:         ptr.Bool(true),
-				}},
-				Labels: MakeLabels(addressable),
-			},
-			Spec: eventingv1alpha1.TriggerSpec{
-				Broker: filter.Broker(),
-				Filter: &eventingv1alpha1.TriggerFilter{
-					Attributes: filter.Filters(),
-				},
-				Subscriber: subscriber,
-			},
-		}
-		triggers = append(triggers, t)
-	}
-
-	return triggers, nil
+// MakeTriggers synthesizes the Triggers of an Addressable, for the entries of
+// its filter annotation and the extra entries given, such as the ones of the
+// profiles it references or the bindings that select it. The filters of the
+// Triggers in another namespace, which wait for the Addressable to have an
+// address, are returned as pending.
+func MakeTriggers(addressable *duckv1.AddressableType, cfg *config.AutoTrigger, extra ...autotrigger.Filter) ([]*eventingv1alpha1.Trigger, []autotrigger.Filter, error) {
+	return autotrigger.Synthesize(addressable, cfg.Synthesis(), extra...)
 }
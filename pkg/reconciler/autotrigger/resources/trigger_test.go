@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+)
+
+func TestMakeTriggersCrossNamespace(t *testing.T) {
+	cfg := config.FromContextOrDefaults(context.Background()).AutoTrigger
+	addressable := &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			UID:       "abc-123",
+			Labels:    map[string]string{AutoTriggerLabel: "true"},
+			Annotations: map[string]string{
+				FilterAnnotation: `[{"type":"local"},{"broker":"events/shared","type":"remote"}]`,
+			},
+		},
+	}
+
+	// Without an address only the local Trigger can be made.
+	triggers, pending, err := MakeTriggers(addressable, cfg)
+	if err != nil {
+		t.Fatalf("MakeTriggers() = %v", err)
+	}
+	if len(triggers) != 1 {
+		t.Fatalf("MakeTriggers() made %d triggers, wanted 1", len(triggers))
+	}
+	if len(pending) != 1 || pending[0].Broker != "events/shared" {
+		t.Errorf("MakeTriggers() pending = %v, wanted the events/shared filter", pending)
+	}
+	if got := triggers[0]; got.Namespace != "default" || got.Spec.Broker != "default" || !metav1.IsControlledBy(got, addressable) {
+		t.Errorf("local Trigger = %+v", got)
+	}
+
+	addressable.Status.Address = &duckv1.Addressable{
+		URL: &apis.URL{Scheme: "http", Host: "foo.default.svc.cluster.local"},
+	}
+	triggers, pending, err = MakeTriggers(addressable, cfg)
+	if err != nil {
+		t.Fatalf("MakeTriggers() = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("MakeTriggers() pending = %v, wanted none", pending)
+	}
+	if len(triggers) != 2 {
+		t.Fatalf("MakeTriggers() made %d triggers, wanted 2", len(triggers))
+	}
+	remote := triggers[1]
+	if remote.Namespace != "events" || remote.Spec.Broker != "shared" {
+		t.Errorf("remote Trigger is for %s/%s, wanted events/shared", remote.Namespace, remote.Spec.Broker)
+	}
+	if len(remote.OwnerReferences) != 0 {
+		t.Errorf("remote Trigger has owner references %v", remote.OwnerReferences)
+	}
+	if got, want := remote.Spec.Subscriber.URI.String(), "http://foo.default.svc.cluster.local"; got != want {
+		t.Errorf("remote Trigger subscriber = %q, wanted %q", got, want)
+	}
+	if got := remote.Labels[ownerUIDLabel]; got != "abc-123" {
+		t.Errorf("remote Trigger owner UID label = %q, wanted %q", got, "abc-123")
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	listers "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/scope"
+)
+
+// Reconciler implements controller.Reconciler for AutoTriggerBindings. The
+// Triggers themselves are made by the autotrigger controllers, this only
+// keeps the status of the binding up to date.
+type Reconciler struct {
+	dynamicClientSet dynamic.Interface
+	bindingLister    listers.AutoTriggerBindingLister
+	triggerIndexer   cache.Indexer
+	crdLister        apiextensionslisters.CustomResourceDefinitionLister
+	scope            *scope.Scope
+	namespaceLister  corev1listers.NamespaceLister
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ controller.Reconciler = (*Reconciler)(nil)
+
+// Reconcile
+func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorf("invalid resource key: %s", key)
+		return nil
+	}
+
+	if ok, err := c.scope.Allows(c.namespaceLister, namespace); err != nil {
+		return err
+	} else if !ok {
+		logger.Debugf("%s is out of the scope of the controller", key)
+		return nil
+	}
+
+	original, err := c.bindingLister.AutoTriggerBindings(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		logger.Infof("AutoTriggerBinding %q in work queue no longer exists", key)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Don't modify the informers copy
+	binding := original.DeepCopy()
+	if err := c.reconcile(ctx, binding); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(original.Status, binding.Status) {
+		return nil
+	}
+	return c.updateStatus(binding)
+}
+
+func (c *Reconciler) reconcile(ctx context.Context, binding *autotriggerv1alpha1.AutoTriggerBinding) error {
+	logger := logging.FromContext(ctx)
+
+	binding.Status.ObservedGeneration = binding.Generation
+	binding.Status.Subjects = nil
+
+	gvk := schema.FromAPIVersionAndKind(binding.Spec.Subject.APIVersion, binding.Spec.Subject.Kind)
+	gvr, err := c.resourceFor(gvk)
+	if err != nil {
+		logger.Infof("AutoTriggerBinding %s/%s: %v", binding.Namespace, binding.Name, err)
+		return nil
+	}
+
+	selector, err := resources.BindingSelector(binding)
+	if err != nil {
+		logger.Infof("AutoTriggerBinding %s/%s: invalid selector: %v", binding.Namespace, binding.Name, err)
+		return nil
+	}
+
+	subjects, err := c.dynamicClientSet.Resource(gvr).Namespace(binding.Namespace).List(metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, subject := range subjects.Items {
+		triggers, err := c.triggersFor(&subject)
+		if err != nil {
+			return err
+		}
+		binding.Status.Subjects = append(binding.Status.Subjects, autotriggerv1alpha1.BoundSubject{
+			APIVersion: binding.Spec.Subject.APIVersion,
+			Kind:       binding.Spec.Subject.Kind,
+			Name:       subject.GetName(),
+			Triggers:   triggers,
+		})
+	}
+	sort.Slice(binding.Status.Subjects, func(i, j int) bool {
+		return binding.Status.Subjects[i].Name < binding.Status.Subjects[j].Name
+	})
+	return nil
+}
+
+// resourceFor finds the resource of an Addressable kind from its
+// CustomResourceDefinition. Bindings match the kind at any version, so when
+// the version of the subject is not served the storage version is used.
+func (c *Reconciler) resourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	crds, err := c.crdLister.List(labels.Everything())
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	for _, crd := range crds {
+		if crd.Spec.Group != gvk.Group || crd.Spec.Names.Kind != gvk.Kind {
+			continue
+		}
+		storage := ""
+		for _, v := range crd.Spec.Versions {
+			if v.Name == gvk.Version && v.Served {
+				return gvk.GroupVersion().WithResource(crd.Spec.Names.Plural), nil
+			}
+			if v.Storage && v.Served {
+				storage = v.Name
+			}
+		}
+		if storage != "" {
+			return schema.GroupVersionResource{Group: gvk.Group, Version: storage, Resource: crd.Spec.Names.Plural}, nil
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("no resource found for %s", gvk)
+}
+
+// triggersFor returns the names of the Triggers created for the subject.
+// Triggers in another namespace are named namespace/name.
+func (c *Reconciler) triggersFor(subject *unstructured.Unstructured) ([]string, error) {
+	objs, err := c.triggerIndexer.ByIndex(resources.OwnerUIDIndex, string(subject.GetUID()))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		trigger, ok := obj.(*eventingv1alpha1.Trigger)
+		if !ok {
+			continue
+		}
+		if trigger.Namespace == subject.GetNamespace() {
+			names = append(names, trigger.Name)
+		} else {
+			names = append(names, trigger.Namespace+"/"+trigger.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *Reconciler) updateStatus(binding *autotriggerv1alpha1.AutoTriggerBinding) error {
+	binding.SetGroupVersionKind(autotriggerv1alpha1.SchemeGroupVersion.WithKind("AutoTriggerBinding"))
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(binding)
+	if err != nil {
+		return err
+	}
+	_, err = c.dynamicClientSet.Resource(autotriggerv1alpha1.SchemeGroupVersion.WithResource("autotriggerbindings")).
+		Namespace(binding.Namespace).
+		UpdateStatus(&unstructured.Unstructured{Object: u}, metav1.UpdateOptions{})
+	return err
+}
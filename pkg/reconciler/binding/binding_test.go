@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+	. "github.com/n3wscott/autotrigger/pkg/reconciler/testing"
+)
+
+const testNS = "test-namespace"
+
+var widgetsCRD = &v1beta1.CustomResourceDefinition{
+	ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.dev"},
+	Spec: v1beta1.CustomResourceDefinitionSpec{
+		Group: "example.dev",
+		Names: v1beta1.CustomResourceDefinitionNames{
+			Plural: "widgets",
+			Kind:   "Widget",
+		},
+		Versions: []v1beta1.CustomResourceDefinitionVersion{{
+			Name:   "v1alpha1",
+			Served: true,
+		}, {
+			Name:    "v1",
+			Served:  true,
+			Storage: true,
+		}},
+	},
+}
+
+func newWidget(name string, labels map[string]string) *duckv1.AddressableType {
+	return &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "example.dev/v1",
+			Kind:       "Widget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNS,
+			Name:      name,
+			UID:       types.UID(name + "-uid"),
+			Labels:    labels,
+		},
+	}
+}
+
+func newBinding(apiVersion string, selector map[string]string) *autotriggerv1alpha1.AutoTriggerBinding {
+	b := &autotriggerv1alpha1.AutoTriggerBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  testNS,
+			Name:       "widgets",
+			Generation: 2,
+		},
+		Spec: autotriggerv1alpha1.AutoTriggerBindingSpec{
+			Subject: autotriggerv1alpha1.BindingSubject{
+				APIVersion: apiVersion,
+				Kind:       "Widget",
+			},
+		},
+	}
+	if selector != nil {
+		b.Spec.Subject.Selector = &metav1.LabelSelector{MatchLabels: selector}
+	}
+	return b
+}
+
+// withSubjects sets the status the reconciler reports, and the TypeMeta the
+// status is updated with.
+func withSubjects(b *autotriggerv1alpha1.AutoTriggerBinding, subjects ...autotriggerv1alpha1.BoundSubject) *autotriggerv1alpha1.AutoTriggerBinding {
+	b = b.DeepCopy()
+	b.SetGroupVersionKind(autotriggerv1alpha1.SchemeGroupVersion.WithKind("AutoTriggerBinding"))
+	b.Status.ObservedGeneration = b.Generation
+	b.Status.Subjects = subjects
+	return b
+}
+
+func newTrigger(namespace, name string, owner *duckv1.AddressableType) *eventingv1alpha1.Trigger {
+	return &eventingv1alpha1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{autotrigger.OwnerUIDLabel: string(owner.UID)},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	key := testNS + "/widgets"
+
+	blue := newWidget("blue", map[string]string{"color": "blue"})
+	red := newWidget("red", map[string]string{"color": "red"})
+	other := newWidget("other", map[string]string{"color": "blue"})
+	other.Namespace = "elsewhere"
+
+	triggers := []runtime.Object{
+		newTrigger(testNS, "blue-b", blue),
+		newTrigger(testNS, "blue-a", blue),
+		newTrigger("events", "blue-c", blue),
+		newTrigger(testNS, "red-a", red),
+	}
+	blueSubject := autotriggerv1alpha1.BoundSubject{
+		APIVersion: "example.dev/v1",
+		Kind:       "Widget",
+		Name:       "blue",
+		Triggers:   []string{"blue-a", "blue-b", "events/blue-c"},
+	}
+	redSubject := autotriggerv1alpha1.BoundSubject{
+		APIVersion: "example.dev/v1",
+		Kind:       "Widget",
+		Name:       "red",
+		Triggers:   []string{"red-a"},
+	}
+
+	objects := func(objs ...runtime.Object) []runtime.Object {
+		return append(append([]runtime.Object{widgetsCRD, blue, red, other}, triggers...), objs...)
+	}
+
+	table := TableTest{{
+		Name: "bad key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "binding not found",
+		Key:  key,
+	}, {
+		Name:              "all subjects of the kind",
+		Key:               key,
+		Objects:           objects(newBinding("example.dev/v1", nil)),
+		WantStatusUpdates: []runtime.Object{withSubjects(newBinding("example.dev/v1", nil), blueSubject, redSubject)},
+	}, {
+		Name:              "subjects matching the selector",
+		Key:               key,
+		Objects:           objects(newBinding("example.dev/v1", map[string]string{"color": "red"})),
+		WantStatusUpdates: []runtime.Object{withSubjects(newBinding("example.dev/v1", map[string]string{"color": "red"}), redSubject)},
+	}, {
+		Name:    "subject at an unserved version",
+		Key:     key,
+		Objects: objects(newBinding("example.dev/v2", map[string]string{"color": "red"})),
+		WantStatusUpdates: []runtime.Object{withSubjects(newBinding("example.dev/v2", map[string]string{"color": "red"}), autotriggerv1alpha1.BoundSubject{
+			APIVersion: "example.dev/v2",
+			Kind:       "Widget",
+			Name:       "red",
+			Triggers:   []string{"red-a"},
+		})},
+	}, {
+		Name:              "no resource for the kind",
+		Key:               key,
+		Objects:           []runtime.Object{newBinding("example.dev/v1", nil), blue},
+		WantStatusUpdates: []runtime.Object{withSubjects(newBinding("example.dev/v1", nil))},
+	}, {
+		Name:    "status up to date",
+		Key:     key,
+		Objects: objects(withSubjects(newBinding("example.dev/v1", nil), blueSubject, redSubject)),
+	}, {
+		Name:              "stale status",
+		Key:               key,
+		Objects:           objects(withSubjects(newBinding("example.dev/v1", nil), blueSubject)),
+		WantStatusUpdates: []runtime.Object{withSubjects(newBinding("example.dev/v1", nil), blueSubject, redSubject)},
+	}, {
+		Name:              "status update fails",
+		Key:               key,
+		Objects:           objects(newBinding("example.dev/v1", nil)),
+		WithReactors:      []Reactor{InduceFailure(VerbUpdateStatus, "autotriggerbindings")},
+		WantErr:           true,
+		WantStatusUpdates: []runtime.Object{withSubjects(newBinding("example.dev/v1", nil), blueSubject, redSubject)},
+	}}
+
+	table.Test(t, func(t *testing.T, r *TableRow, f Fakes) controller.Reconciler {
+		return &Reconciler{
+			dynamicClientSet: f.Dynamic(),
+			bindingLister:    f.Listers.GetAutoTriggerBindingLister(),
+			triggerIndexer:   f.Listers.GetTriggerIndexer(),
+			crdLister:        f.Listers.GetCustomResourceDefinitionLister(),
+			namespaceLister:  f.Listers.GetNamespaceLister(),
+		}
+	})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+
+	triggerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/trigger"
+	crdinformer "knative.dev/pkg/client/injection/apiextensions/informers/apiextensions/v1beta1/customresourcedefinition"
+	"knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+
+	bindinginformer "github.com/n3wscott/autotrigger/pkg/client/injection/informers/autotrigger/v1alpha1/autotriggerbinding"
+	listers "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/scope"
+)
+
+// NewController creates the controller that reports the Addressables matched
+// by AutoTriggerBindings and the Triggers created for them.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	logger := logging.FromContext(ctx)
+
+	bindingInformer := bindinginformer.Get(ctx)
+	triggerInformer := triggerinformer.Get(ctx)
+
+	if err := resources.AddOwnerUIDIndex(triggerInformer.Informer()); err != nil {
+		logger.Fatalw("failed to index Triggers", zap.Error(err))
+	}
+
+	c := &Reconciler{
+		dynamicClientSet: dynamicclient.Get(ctx),
+		bindingLister:    bindingInformer.Lister(),
+		triggerIndexer:   triggerInformer.Informer().GetIndexer(),
+		crdLister:        crdinformer.Get(ctx).Lister(),
+		scope:            scope.FromContext(ctx),
+		namespaceLister:  namespace.Get(ctx).Lister(),
+	}
+	impl := controller.NewImpl(c, logger, "AutoTriggerBindings")
+
+	logger.Info("Setting up event handlers")
+
+	bindingInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	// Triggers are created and deleted by the autotrigger controllers, so
+	// look again at the bindings of the namespace the Trigger was made for.
+	triggerInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+		enqueueBindings(impl, bindingInformer.Lister(), obj)
+	}))
+
+	return impl
+}
+
+func enqueueBindings(impl *controller.Impl, bindingLister listers.AutoTriggerBindingLister, obj interface{}) {
+	object, err := kmeta.DeletionHandlingAccessor(obj)
+	if err != nil {
+		return
+	}
+	bindings, err := bindingLister.AutoTriggerBindings(resources.OwnerNamespace(object)).List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, binding := range bindings {
+		impl.Enqueue(binding)
+	}
+}
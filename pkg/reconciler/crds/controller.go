@@ -2,12 +2,28 @@ package crds
 
 import (
 	"context"
+	"time"
 
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 
+	triggerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/trigger"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/ratelimit"
 	_ "github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/scope"
+	"github.com/n3wscott/autotrigger/pkg/tracing"
 	crdinfomer "knative.dev/pkg/client/injection/apiextensions/informers/apiextensions/v1beta1/customresourcedefinition"
 )
 
@@ -19,10 +35,58 @@ func NewController(
 
 	crdInformer := crdinfomer.Get(ctx)
 
+	// The autotrigger controllers report on the Addressables they act on.
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logger.Named("event-broadcaster").Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclient.Get(ctx).CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "autotrigger-controller"})
+
+	// With a list of namespaces, an autotrigger controller runs for each of
+	// them, on the informers of its namespace.
+	nsctxs := append([]context.Context{ctx}, scope.NamespaceContexts(ctx)...)
+
+	// The autotrigger controllers look up Triggers by owner, but start after
+	// the shared Trigger informers, so the index is added from here.
+	triggerListers := make([]eventinglisters.TriggerLister, 0, len(nsctxs))
+	for _, nsctx := range nsctxs {
+		triggerInformer := triggerinformer.Get(nsctx)
+		if err := resources.AddOwnerUIDIndex(triggerInformer.Informer()); err != nil {
+			logger.Fatalw("failed to index Triggers", zap.Error(err))
+		}
+		triggerListers = append(triggerListers, triggerInformer.Lister())
+	}
+
+	stats := metrics.NewStatsReporter()
+	go reportTriggers(ctx, triggerListers, stats, 30*time.Second)
+
+	// The autotrigger controllers are started after the ConfigMap watcher,
+	// so the configuration they share is watched from here.
+	configStore := config.NewStore(logger.Named("config-store"), func(name string, value interface{}) {
+		if budget := ratelimit.BudgetFromContext(ctx); budget != nil && name == config.ConfigName {
+			writes := value.(*config.AutoTrigger).Writes
+			budget.SetLimits(writes.QPS, writes.Burst)
+		}
+	})
+	configStore.WatchConfigs(cmw)
+
+	tracer := tracing.NewTracer(logger.Named("tracer"))
+	cmw.Watch(tracing.ConfigName, tracer.UpdateFromConfigMap)
+	go func() {
+		<-ctx.Done()
+		tracer.Flush()
+	}()
+
+	ogctxs := make([]context.Context, 0, len(nsctxs))
+	for _, nsctx := range nsctxs {
+		ogctxs = append(ogctxs, controller.WithEventRecorder(nsctx, recorder))
+	}
+
 	c := &Reconciler{
-		crdLister: crdInformer.Lister(),
-		ogctx:     ctx,
-		ogcmw:     cmw,
+		crdLister:   crdInformer.Lister(),
+		ogctxs:      ogctxs,
+		ogcmw:       cmw,
+		configStore: configStore,
+		stats:       stats,
 	}
 	impl := controller.NewImpl(c, logger, "AddressableCRDs")
 
@@ -30,13 +30,16 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 
+	"github.com/n3wscott/autotrigger/pkg/metrics"
 	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
 )
 
 type runningController struct {
-	gvr        schema.GroupVersionResource
-	controller *controller.Impl
-	cancel     context.CancelFunc
+	gvr schema.GroupVersionResource
+	// controllers has one controller for each context of the Reconciler.
+	controllers []*controller.Impl
+	cancel      context.CancelFunc
 }
 
 var addressable = `duck.knative.dev/addressable`
@@ -47,8 +50,13 @@ type Reconciler struct {
 	// Injected
 
 	crdLister apiextensionsv1beta1.CustomResourceDefinitionLister
-	ogctx     context.Context
-	ogcmw     configmap.Watcher
+	// ogctxs are the contexts the autotrigger controllers run in, one for
+	// each namespace of the scope, or a single one watching them all.
+	ogctxs []context.Context
+	ogcmw  configmap.Watcher
+
+	configStore *config.Store
+	stats       metrics.StatsReporter
 
 	// Local state
 
@@ -97,6 +105,10 @@ func (c *Reconciler) Reconcile(ctx context.Context, key string) error {
 func (c *Reconciler) ensureAddressableController(ctx context.Context, crd *v1beta1.CustomResourceDefinition) error {
 	logger := logging.FromContext(ctx)
 	var gvr *schema.GroupVersionResource
+	gk := schema.GroupKind{
+		Group: crd.Spec.Group,
+		Kind:  crd.Spec.Names.Kind,
+	}
 
 	for _, v := range crd.Spec.Versions {
 		if !v.Served {
@@ -128,34 +140,45 @@ func (c *Reconciler) ensureAddressableController(ctx context.Context, crd *v1bet
 			c.lock.Lock()
 			rc.cancel()
 			delete(c.controllers, *gvr)
+			c.stats.ReportWatchedResources(len(c.controllers))
 			c.lock.Unlock()
 		}
 		return nil
 	}
 
 	// Auto Trigger Constructor
-	atc := autotrigger.NewControllerConstructor(crd.ClusterName, *gvr, c)
-	// Auto Trigger Context
-	atctx, cancel := context.WithCancel(c.ogctx)
-	// Auto Trigger
-	at := atc(atctx, c.ogcmw)
-
-	rc = runningController{
-		gvr:        *gvr,
-		controller: at,
-		cancel:     cancel,
+	atc := autotrigger.NewControllerConstructor(crd.ClusterName, *gvr, gk, c, c.configStore)
+
+	rc = runningController{gvr: *gvr}
+	cancels := make([]context.CancelFunc, 0, len(c.ogctxs))
+	atctxs := make([]context.Context, 0, len(c.ogctxs))
+	for _, ogctx := range c.ogctxs {
+		// Auto Trigger Context
+		atctx, cancel := context.WithCancel(ogctx)
+		// Auto Trigger
+		rc.controllers = append(rc.controllers, atc(atctx, c.ogcmw))
+		cancels = append(cancels, cancel)
+		atctxs = append(atctxs, atctx)
+	}
+	rc.cancel = func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
 	}
 
 	c.lock.Lock()
 	c.controllers[rc.gvr] = rc
+	c.stats.ReportWatchedResources(len(c.controllers))
 	c.lock.Unlock()
 
 	logger.Infof("starting autotrigger reconciler for gvr %q", rc.gvr.String())
-	go func(c *controller.Impl) {
-		if err := c.Run(2, atctx.Done()); err != nil {
-			logger.Errorf("unable to start autotrigger reconciler for gvr %q", rc.gvr.String())
-		}
-	}(rc.controller)
+	for i, at := range rc.controllers {
+		go func(c *controller.Impl, stopCh <-chan struct{}) {
+			if err := c.Run(2, stopCh); err != nil {
+				logger.Errorf("unable to start autotrigger reconciler for gvr %q", rc.gvr.String())
+			}
+		}(at, atctxs[i].Done())
+	}
 
 	logger.Infof("-----AutoTriggering-------")
 	for k, _ := range c.controllers {
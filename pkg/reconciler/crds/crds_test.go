@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crds
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/controller"
+
+	. "github.com/n3wscott/autotrigger/pkg/reconciler/testing"
+)
+
+const crdName = "widgets.example.dev"
+
+var (
+	widgetsGVR = schema.GroupVersionResource{Group: "example.dev", Version: "v1", Resource: "widgets"}
+	gadgetsGVR = schema.GroupVersionResource{Group: "example.dev", Version: "v1", Resource: "gadgets"}
+)
+
+func newCRD(labels map[string]string, deleting bool) *v1beta1.CustomResourceDefinition {
+	crd := &v1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   crdName,
+			Labels: labels,
+		},
+		Spec: v1beta1.CustomResourceDefinitionSpec{
+			Group: "example.dev",
+			Names: v1beta1.CustomResourceDefinitionNames{
+				Plural: "widgets",
+				Kind:   "Widget",
+			},
+			Versions: []v1beta1.CustomResourceDefinitionVersion{{
+				Name:   "v1",
+				Served: true,
+			}},
+		},
+	}
+	if deleting {
+		now := metav1.Now()
+		crd.DeletionTimestamp = &now
+	}
+	return crd
+}
+
+func TestReconcile(t *testing.T) {
+	watching := func(want int) func(*testing.T, *TableRow, Fakes) {
+		return func(t *testing.T, _ *TableRow, f Fakes) {
+			if got := f.Stats.WatchedResources; got != want {
+				t.Errorf("WatchedResources = %d, wanted %d", got, want)
+			}
+		}
+	}
+
+	table := TableTest{{
+		Name: "bad key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "crd not found",
+		Key:  crdName,
+	}, {
+		Name:    "crd not addressable",
+		Key:     crdName,
+		Objects: []runtime.Object{newCRD(nil, false)},
+	}, {
+		Name:    "addressable crd already watched",
+		Key:     crdName,
+		Objects: []runtime.Object{newCRD(map[string]string{addressable: "true"}, false)},
+		OtherTestData: map[string]interface{}{
+			"running": []schema.GroupVersionResource{widgetsGVR, gadgetsGVR},
+		},
+	}, {
+		Name:    "addressable crd deleted",
+		Key:     crdName,
+		Objects: []runtime.Object{newCRD(map[string]string{addressable: "true"}, true)},
+		OtherTestData: map[string]interface{}{
+			"running": []schema.GroupVersionResource{widgetsGVR, gadgetsGVR},
+		},
+		PostConditions: []func(*testing.T, *TableRow, Fakes){watching(1)},
+	}}
+
+	table.Test(t, func(t *testing.T, r *TableRow, f Fakes) controller.Reconciler {
+		c := &Reconciler{
+			crdLister:   f.Listers.GetCustomResourceDefinitionLister(),
+			ogctxs:      []context.Context{context.Background()},
+			stats:       f.Stats,
+			controllers: make(map[schema.GroupVersionResource]runningController),
+		}
+		running, _ := r.OtherTestData["running"].([]schema.GroupVersionResource)
+		for _, gvr := range running {
+			c.controllers[gvr] = runningController{gvr: gvr, cancel: func() {}}
+		}
+		return c
+	})
+}
+
+func TestIsGVKAddressable(t *testing.T) {
+	c := &Reconciler{
+		kToR: map[schema.GroupVersionKind]schema.GroupVersionResource{
+			widgetsGVR.GroupVersion().WithKind("Widget"): widgetsGVR,
+		},
+	}
+	if !c.IsGVKAddressable(context.Background(), widgetsGVR.GroupVersion().WithKind("Widget")) {
+		t.Error("IsGVKAddressable(Widget) = false, wanted true")
+	}
+	if c.IsGVKAddressable(context.Background(), gadgetsGVR.GroupVersion().WithKind("Gadget")) {
+		t.Error("IsGVKAddressable(Gadget) = true, wanted false")
+	}
+}
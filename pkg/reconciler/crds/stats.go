@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crds
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+)
+
+// reportTriggers reports the number of Triggers made by autotrigger in each
+// namespace, and how many of them are not Ready, every period until ctx is
+// done. The listers each serve the Triggers of different namespaces.
+func reportTriggers(ctx context.Context, triggerListers []eventinglisters.TriggerLister, stats metrics.StatsReporter, period time.Duration) {
+	logger := logging.FromContext(ctx)
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	// Namespaces reported last time, so that they drop to zero once their
+	// last Trigger is gone.
+	reported := make(map[string]struct{})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var (
+			triggers []*eventingv1alpha1.Trigger
+			err      error
+		)
+		for _, triggerLister := range triggerListers {
+			var listed []*eventingv1alpha1.Trigger
+			if listed, err = triggerLister.List(labels.SelectorFromSet(resources.MakeManagedSelector())); err != nil {
+				break
+			}
+			triggers = append(triggers, listed...)
+		}
+		if err != nil {
+			logger.Errorf("failed to list Triggers: %v", err)
+			continue
+		}
+
+		managed := make(map[string]int)
+		notReady := make(map[string]int)
+		for _, trigger := range triggers {
+			managed[trigger.Namespace]++
+			if !trigger.Status.IsReady() {
+				notReady[trigger.Namespace]++
+			}
+		}
+		for namespace := range reported {
+			if _, ok := managed[namespace]; !ok {
+				stats.ReportTriggers(namespace, 0, 0)
+			}
+		}
+		reported = make(map[string]struct{}, len(managed))
+		for namespace, count := range managed {
+			stats.ReportTriggers(namespace, count, notReady[namespace])
+			reported[namespace] = struct{}{}
+		}
+	}
+}
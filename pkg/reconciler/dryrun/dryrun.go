@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun reports the writes the reconcilers skip in dry-run mode.
+package dryrun
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+)
+
+// Enabled reports whether dry-run mode is on in the configuration attached to
+// ctx.
+func Enabled(ctx context.Context) bool {
+	return config.FromContextOrDefaults(ctx).AutoTrigger.DryRun
+}
+
+// Reporter reports the writes skipped in dry-run mode.
+type Reporter struct {
+	// Resource is the resource of the objects reconciled, which the metric
+	// is tagged with.
+	Resource string
+	Stats    metrics.StatsReporter
+	Recorder record.EventRecorder
+}
+
+// Report reports that the operation on the kind named namespace/name was
+// skipped, with a log line, a metric and a DryRun event on the object being
+// reconciled.
+func (r *Reporter) Report(ctx context.Context, object runtime.Object, operation, kind, namespace, name string) {
+	logging.FromContext(ctx).Infof("dry run: would %s %s %s/%s", operation, kind, namespace, name)
+	if r.Stats != nil {
+		r.Stats.ReportDryRunOperation(r.Resource, operation)
+	}
+	if r.Recorder != nil && object != nil {
+		r.Recorder.Eventf(object, corev1.EventTypeNormal, "DryRun", "Would %s %s %s/%s", operation, kind, namespace, name)
+	}
+}
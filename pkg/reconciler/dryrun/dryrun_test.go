@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	. "github.com/n3wscott/autotrigger/pkg/reconciler/testing"
+)
+
+func TestEnabled(t *testing.T) {
+	if Enabled(context.Background()) {
+		t.Error("Enabled() = true without a configuration, wanted false")
+	}
+	cfg, err := config.NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.ConfigName},
+		Data:       map[string]string{"dry-run": "true"},
+	})
+	if err != nil {
+		t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+	}
+	if !Enabled(config.ToContext(context.Background(), &config.Config{AutoTrigger: cfg})) {
+		t.Error("Enabled() = false with dry-run on, wanted true")
+	}
+}
+
+func TestReport(t *testing.T) {
+	stats := &FakeStatsReporter{}
+	recorder := record.NewFakeRecorder(2)
+	r := &Reporter{Resource: "sequences", Stats: stats, Recorder: recorder}
+
+	object := &corev1.ObjectReference{Kind: "Sequence", Namespace: "default", Name: "flow"}
+	r.Report(context.Background(), object, "create", "Trigger", "default", "flow-default")
+	// Without an object there is nothing to record the event on.
+	r.Report(context.Background(), nil, "delete", "Trigger", "default", "flow-other")
+
+	if diff := cmp.Diff(map[string]int{"create": 1, "delete": 1}, stats.DryRunOperations); diff != "" {
+		t.Errorf("DryRunOperations (-want, +got) = %s", diff)
+	}
+	if got, want := <-recorder.Events, Eventf(corev1.EventTypeNormal, "DryRun", "Would create Trigger default/flow-default"); got != want {
+		t.Errorf("event = %q, wanted %q", got, want)
+	}
+	select {
+	case event := <-recorder.Events:
+		t.Errorf("unexpected event %q", event)
+	default:
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventtypes reconciles the EventTypes declared by producers. It is
+// shared by the autotrigger and autosink reconcilers.
+package eventtypes
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/eventtypes/resources"
+)
+
+// Reconciler makes the EventTypes owned by a producer match the ones it
+// declares.
+type Reconciler struct {
+	EventingClientSet eventingclientset.Interface
+	EventTypeLister   eventinglisters.EventTypeLister
+
+	// DryRun reports the EventTypes that are not created or deleted in
+	// dry-run mode.
+	DryRun dryrun.Reporter
+}
+
+// Reconcile creates the EventTypes declared by producer that do not exist yet
+// and deletes the ones owned by producer that are no longer declared.
+func (r *Reconciler) Reconcile(ctx context.Context, producer resources.Producer, defaultBroker string) error {
+	desired, err := resources.MakeEventTypes(producer, defaultBroker)
+	if err != nil {
+		return err
+	}
+	return r.reconcile(ctx, producer, desired)
+}
+
+// Delete deletes the EventTypes owned by producer.
+func (r *Reconciler) Delete(ctx context.Context, producer resources.Producer) error {
+	return r.reconcile(ctx, producer, nil)
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, producer resources.Producer, desired []*eventingv1alpha1.EventType) error {
+	logger := logging.FromContext(ctx)
+
+	existing, err := r.EventTypeLister.EventTypes(producer.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	existing = filterEventTypes(producer, existing)
+
+	for _, et := range desired {
+		var found *eventingv1alpha1.EventType
+		existing, found = extractEventTypeLike(existing, et)
+		if found != nil {
+			continue
+		}
+		if dryrun.Enabled(ctx) {
+			r.DryRun.Report(ctx, producer, metrics.OperationCreate, "EventType", et.Namespace, et.Name)
+			continue
+		}
+		// The EventType is named by its spec, so it already existing means
+		// the informer has not seen it yet.
+		if _, err := r.EventingClientSet.EventingV1alpha1().EventTypes(producer.GetNamespace()).Create(et); err != nil && !apierrs.IsAlreadyExists(err) {
+			logger.Errorf("failed to create EventType %q: %v", et.Spec.Type, err)
+			return err
+		}
+	}
+
+	// Delete all the remaining event types.
+	for _, et := range existing {
+		if dryrun.Enabled(ctx) {
+			r.DryRun.Report(ctx, producer, metrics.OperationDelete, "EventType", et.Namespace, et.Name)
+			continue
+		}
+		if err := r.EventingClientSet.EventingV1alpha1().EventTypes(producer.GetNamespace()).Delete(et.Name, &metav1.DeleteOptions{}); err != nil {
+			logger.Errorf("failed to delete EventType %q: %v", et.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns a handler for the EventType informer that enqueues the
+// producer of the kind controlling the EventType, so that EventTypes deleted
+// or changed by others are made again.
+func Handler(impl *controller.Impl, gk schema.GroupKind) cache.ResourceEventHandler {
+	return controller.HandleAll(func(obj interface{}) {
+		et, err := kmeta.DeletionHandlingAccessor(obj)
+		if err != nil {
+			return
+		}
+		owner := metav1.GetControllerOf(et)
+		if owner == nil || schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind).GroupKind() != gk {
+			return
+		}
+		impl.EnqueueKey(types.NamespacedName{Namespace: et.GetNamespace(), Name: owner.Name})
+	})
+}
+
+func filterEventTypes(producer metav1.Object, eventTypes []*eventingv1alpha1.EventType) []*eventingv1alpha1.EventType {
+	filtered := []*eventingv1alpha1.EventType(nil)
+	for _, et := range eventTypes {
+		if metav1.IsControlledBy(et, producer) {
+			filtered = append(filtered, et)
+		}
+	}
+	return filtered
+}
+
+func extractEventTypeLike(eventTypes []*eventingv1alpha1.EventType, like *eventingv1alpha1.EventType) ([]*eventingv1alpha1.EventType, *eventingv1alpha1.EventType) {
+	for i, et := range eventTypes {
+		if equality.Semantic.DeepEqual(like.Spec, et.Spec) {
+			return append(eventTypes[:i], eventTypes[i+1:]...), et
+		}
+	}
+	return eventTypes, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	producesAnnotation = "eventing.knative.dev/produces"
+)
+
+// Producer is an object that can declare the event types it produces.
+type Producer interface {
+	metav1.Object
+	runtime.Object
+}
+
+type producedType struct {
+	Type        string `json:"type"`
+	Source      string `json:"source"`
+	Schema      string `json:"schema,omitempty"`
+	Broker      string `json:"broker,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (pt producedType) validate() error {
+	if pt.Type == "" {
+		return errors.New("missing field: type")
+	}
+	if pt.Source == "" {
+		return errors.New("missing field: source")
+	}
+	return nil
+}
+
+// MakeEventTypes creates the EventTypes declared by the produces annotation of
+// a producer. Entries without a broker are registered on defaultBroker.
+func MakeEventTypes(producer Producer, defaultBroker string) ([]*eventingv1alpha1.EventType, error) {
+	rawProduces, ok := producer.GetAnnotations()[producesAnnotation]
+	if !ok || rawProduces == "" {
+		return []*eventingv1alpha1.EventType(nil), nil
+	}
+
+	produced := make([]producedType, 0)
+	if err := json.Unmarshal([]byte(rawProduces), &produced); err != nil {
+		return nil, fmt.Errorf("failed to extract produced event types: %s", err.Error())
+	}
+
+	gvk := producer.GetObjectKind().GroupVersionKind()
+
+	eventTypes := make([]*eventingv1alpha1.EventType, 0, len(produced))
+	for i, pt := range produced {
+		if err := pt.validate(); err != nil {
+			return nil, fmt.Errorf("invalid produced event type [%d]: %s", i, err.Error())
+		}
+		broker := pt.Broker
+		if broker == "" {
+			broker = defaultBroker
+		}
+		spec := eventingv1alpha1.EventTypeSpec{
+			Type:        pt.Type,
+			Source:      pt.Source,
+			Schema:      pt.Schema,
+			Broker:      broker,
+			Description: pt.Description,
+		}
+		et := &eventingv1alpha1.EventType{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      eventTypeName(producer, spec),
+				Namespace: producer.GetNamespace(),
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(producer, gvk),
+				},
+			},
+			Spec: spec,
+		}
+		eventTypes = append(eventTypes, et)
+	}
+
+	return eventTypes, nil
+}
+
+// eventTypeName names the EventType of the producer by its spec, so that
+// creating it again before the informer has seen it fails rather than making
+// a duplicate. A changed spec gets a new name, and the old EventType is
+// deleted.
+func eventTypeName(producer Producer, spec eventingv1alpha1.EventTypeSpec) string {
+	b, _ := json.Marshal(spec)
+	sum := md5.Sum(append([]byte(producer.GetUID()), b...))
+	return kmeta.ChildName(producer.GetName()+"-", fmt.Sprintf("%x", sum[:5]))
+}
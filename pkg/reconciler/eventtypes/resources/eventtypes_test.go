@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestMakeEventTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		produces *string
+		want     []eventingv1alpha1.EventTypeSpec
+		wantErr  bool
+	}{{
+		name: "no annotation",
+	}, {
+		name:     "default broker",
+		produces: ptr(`[{"type":"dev.example.foo","source":"/foo"}]`),
+		want: []eventingv1alpha1.EventTypeSpec{{
+			Type:   "dev.example.foo",
+			Source: "/foo",
+			Broker: "default",
+		}},
+	}, {
+		name:     "explicit broker and schema",
+		produces: ptr(`[{"type":"dev.example.foo","source":"/foo","schema":"/schema","broker":"other"}]`),
+		want: []eventingv1alpha1.EventTypeSpec{{
+			Type:   "dev.example.foo",
+			Source: "/foo",
+			Schema: "/schema",
+			Broker: "other",
+		}},
+	}, {
+		name:     "missing source",
+		produces: ptr(`[{"type":"dev.example.foo"}]`),
+		wantErr:  true,
+	}, {
+		name:     "bad json",
+		produces: ptr(`[{"type":`),
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &duckv1.AddressableType{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "serving.knative.dev/v1",
+					Kind:       "Service",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "default",
+					UID:       "abc-123",
+				},
+			}
+			if test.produces != nil {
+				p.Annotations = map[string]string{producesAnnotation: *test.produces}
+			}
+
+			got, err := MakeEventTypes(p, "default")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("MakeEventTypes() = %v, wantErr %v", err, test.wantErr)
+			}
+
+			var gotSpecs []eventingv1alpha1.EventTypeSpec
+			for _, et := range got {
+				gotSpecs = append(gotSpecs, et.Spec)
+				if !metav1.IsControlledBy(et, p) {
+					t.Errorf("EventType %v is not controlled by %v", et, p)
+				}
+			}
+			if diff := cmp.Diff(test.want, gotSpecs); diff != "" {
+				t.Errorf("MakeEventTypes (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestEventTypeNames(t *testing.T) {
+	p := &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			UID:       "abc-123",
+			Annotations: map[string]string{
+				producesAnnotation: `[{"type":"dev.example.foo","source":"/foo"},{"type":"dev.example.bar","source":"/foo"}]`,
+			},
+		},
+	}
+	first, err := MakeEventTypes(p, "default")
+	if err != nil {
+		t.Fatalf("MakeEventTypes() = %v", err)
+	}
+	again, err := MakeEventTypes(p, "default")
+	if err != nil {
+		t.Fatalf("MakeEventTypes() = %v", err)
+	}
+	if first[0].Name == "" || first[0].Name != again[0].Name {
+		t.Errorf("EventType names %q and %q, wanted the same name every time", first[0].Name, again[0].Name)
+	}
+	if first[0].Name == first[1].Name {
+		t.Errorf("EventTypes of different specs are both named %q", first[0].Name)
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
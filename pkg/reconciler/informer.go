@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+)
+
+// SelectedInformerFactory is a duck.TypedInformerFactory that only lists and
+// watches the objects matching Selector, in Namespace if it is set.
+type SelectedInformerFactory struct {
+	Client       dynamic.Interface
+	Type         apis.Listable
+	Selector     labels.Selector
+	Namespace    string
+	ResyncPeriod time.Duration
+	StopChannel  <-chan struct{}
+}
+
+// Check that SelectedInformerFactory implements duck.InformerFactory.
+var _ duck.InformerFactory = (*SelectedInformerFactory)(nil)
+
+// Get implements duck.InformerFactory.
+func (sif *SelectedInformerFactory) Get(gvr schema.GroupVersionResource) (cache.SharedIndexInformer, cache.GenericLister, error) {
+	listObj := sif.Type.GetListType()
+	client := sif.Client.Resource(gvr).Namespace(sif.Namespace)
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			ul, err := client.List(sif.selected(opts))
+			if err != nil {
+				return nil, err
+			}
+			res := listObj.DeepCopyObject()
+			if err := duck.FromUnstructured(ul, res); err != nil {
+				return nil, err
+			}
+			return res, nil
+		},
+		WatchFunc: duck.AsStructuredWatcher(func(opts metav1.ListOptions) (watch.Interface, error) {
+			return client.Watch(sif.selected(opts))
+		}, sif.Type),
+	}
+	inf := cache.NewSharedIndexInformer(lw, sif.Type, sif.ResyncPeriod, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+
+	lister := cache.NewGenericLister(inf.GetIndexer(), gvr.GroupResource())
+
+	go inf.Run(sif.StopChannel)
+
+	if ok := cache.WaitForCacheSync(sif.StopChannel, inf.HasSynced); !ok {
+		return nil, nil, fmt.Errorf("failed starting shared index informer for %v with type %T", gvr, sif.Type)
+	}
+
+	return inf, lister, nil
+}
+
+func (sif *SelectedInformerFactory) selected(opts metav1.ListOptions) metav1.ListOptions {
+	if sif.Selector != nil && !sif.Selector.Empty() {
+		opts.LabelSelector = sif.Selector.String()
+	}
+	return opts
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const autoTriggerLabel = "eventing.knative.dev/autotrigger"
+
+var servicesGVR = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "services"}
+
+// listOnlyClient serves a fixed list of objects for any resource, filtered by
+// namespace and label selector, and a watch that never sends anything.
+type listOnlyClient struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+	objs      []unstructured.Unstructured
+	namespace string
+}
+
+func (c *listOnlyClient) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c
+}
+
+func (c *listOnlyClient) Namespace(namespace string) dynamic.ResourceInterface {
+	return &listOnlyClient{objs: c.objs, namespace: namespace}
+}
+
+func (c *listOnlyClient) List(opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{
+		"apiVersion": "serving.knative.dev/v1",
+		"kind":       "ServiceList",
+	}}
+	for _, obj := range c.objs {
+		if (c.namespace == "" || obj.GetNamespace() == c.namespace) && selector.Matches(labels.Set(obj.GetLabels())) {
+			list.Items = append(list.Items, *obj.DeepCopy())
+		}
+	}
+	return list, nil
+}
+
+func (c *listOnlyClient) Watch(metav1.ListOptions) (watch.Interface, error) {
+	return watch.NewFake(), nil
+}
+
+// services makes n Knative Services, every stride-th of them with the
+// autotrigger label.
+func services(n, stride int) []unstructured.Unstructured {
+	objs := make([]unstructured.Unstructured, 0, n)
+	for i := 0; i < n; i++ {
+		obj := unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "serving.knative.dev/v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"namespace": fmt.Sprintf("ns-%d", i%10),
+				"name":      fmt.Sprintf("svc-%d", i),
+				"labels":    map[string]interface{}{"app": fmt.Sprintf("svc-%d", i)},
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"url": fmt.Sprintf("http://svc-%d.ns-%d.svc.cluster.local", i, i%10),
+				},
+			},
+		}}
+		if i%stride == 0 {
+			obj.SetLabels(map[string]string{"app": obj.GetName(), autoTriggerLabel: "true"})
+		}
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+func TestSelectedInformerFactory(t *testing.T) {
+	tests := map[string]struct {
+		selector  labels.Selector
+		namespace string
+		want      int
+	}{
+		"everything": {
+			selector: labels.Everything(),
+			want:     100,
+		},
+		"labeled": {
+			selector: labels.SelectorFromSet(labels.Set{autoTriggerLabel: "true"}),
+			want:     10,
+		},
+		"namespace": {
+			selector:  labels.Everything(),
+			namespace: "ns-3",
+			want:      10,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			factory := &SelectedInformerFactory{
+				Client:       &listOnlyClient{objs: services(100, 10)},
+				Type:         &duckv1.AddressableType{},
+				Selector:     tc.selector,
+				Namespace:    tc.namespace,
+				ResyncPeriod: time.Hour,
+				StopChannel:  stopCh,
+			}
+			_, lister, err := factory.Get(servicesGVR)
+			if err != nil {
+				t.Fatalf("Get() = %v", err)
+			}
+			got, err := lister.List(labels.Everything())
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+			if len(got) != tc.want {
+				t.Errorf("len(List()) = %d, wanted %d", len(got), tc.want)
+			}
+		})
+	}
+}
+
+// BenchmarkSelectedInformerFactory reports the heap held by the informer of
+// 10000 Services, 1 in 100 of them labeled, with and without the selector.
+func BenchmarkSelectedInformerFactory(b *testing.B) {
+	client := &listOnlyClient{objs: services(10000, 100)}
+	for name, selector := range map[string]labels.Selector{
+		"everything": labels.Everything(),
+		"labeled":    labels.SelectorFromSet(labels.Set{autoTriggerLabel: "true"}),
+	} {
+		b.Run(name, func(b *testing.B) {
+			var held int64
+			for i := 0; i < b.N; i++ {
+				stopCh := make(chan struct{})
+				before := heapAlloc()
+				factory := &SelectedInformerFactory{
+					Client:       client,
+					Type:         &duckv1.AddressableType{},
+					Selector:     selector,
+					ResyncPeriod: time.Hour,
+					StopChannel:  stopCh,
+				}
+				inf, _, err := factory.Get(servicesGVR)
+				if err != nil {
+					b.Fatalf("Get() = %v", err)
+				}
+				held += heapAlloc() - before
+				runtime.KeepAlive(inf)
+				close(stopCh)
+			}
+			b.ReportMetric(float64(held)/float64(b.N), "heap-B/op")
+		})
+	}
+}
+
+func heapAlloc() int64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapAlloc)
+}
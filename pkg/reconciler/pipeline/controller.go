@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	triggerinformer "knative.dev/eventing/pkg/client/injection/informers/eventing/v1alpha1/trigger"
+	parallelinformer "knative.dev/eventing/pkg/client/injection/informers/messaging/v1alpha1/parallel"
+	sequenceinformer "knative.dev/eventing/pkg/client/injection/informers/messaging/v1alpha1/sequence"
+	"knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	policyinformer "github.com/n3wscott/autotrigger/pkg/client/injection/informers/autotrigger/v1alpha1/autotriggerpolicy"
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline/resources"
+)
+
+// NewSequenceController creates the controller for Sequences built from
+// annotated Addressables.
+func NewSequenceController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	logger := logging.FromContext(ctx)
+
+	sequenceInformer := sequenceinformer.Get(ctx)
+	triggerInformer := triggerinformer.Get(ctx)
+	policyInformer := policyinformer.Get(ctx)
+
+	// The configuration is read for dry-run mode.
+	configStore := config.NewStore(logger.Named("config-store"))
+	configStore.WatchConfigs(cmw)
+
+	c := &SequenceReconciler{
+		triggerReconciler: triggerReconciler{
+			eventingClientSet: eventingclient.Get(ctx),
+			triggerLister:     triggerInformer.Lister(),
+			policyLister:      policyInformer.Lister(),
+			namespaceLister:   namespace.Get(ctx).Lister(),
+			recorder:          controller.GetEventRecorder(ctx),
+			configStore:       configStore,
+			dryRun: dryrun.Reporter{
+				Resource: messagingv1alpha1.SchemeGroupVersion.WithResource("sequences").String(),
+				Stats:    metrics.NewStatsReporter(),
+				Recorder: controller.GetEventRecorder(ctx),
+			},
+		},
+		sequenceLister: sequenceInformer.Lister(),
+	}
+	impl := controller.NewImpl(c, logger, "AutoTriggerSequences")
+
+	logger.Info("Setting up event handlers")
+
+	sequenceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: isManaged,
+		Handler:    controller.HandleAll(impl.Enqueue),
+	})
+
+	triggerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(messagingv1alpha1.SchemeGroupVersion.WithKind("Sequence")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	// Policies can allow or deny the Trigger of any pipeline, so look at
+	// them all again.
+	policyInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+		impl.GlobalResync(sequenceInformer.Informer())
+	}))
+
+	return impl
+}
+
+// NewParallelController creates the controller for Parallels built from
+// annotated Addressables.
+func NewParallelController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	logger := logging.FromContext(ctx)
+
+	parallelInformer := parallelinformer.Get(ctx)
+	triggerInformer := triggerinformer.Get(ctx)
+	policyInformer := policyinformer.Get(ctx)
+
+	// The configuration is read for dry-run mode.
+	configStore := config.NewStore(logger.Named("config-store"))
+	configStore.WatchConfigs(cmw)
+
+	c := &ParallelReconciler{
+		triggerReconciler: triggerReconciler{
+			eventingClientSet: eventingclient.Get(ctx),
+			triggerLister:     triggerInformer.Lister(),
+			policyLister:      policyInformer.Lister(),
+			namespaceLister:   namespace.Get(ctx).Lister(),
+			recorder:          controller.GetEventRecorder(ctx),
+			configStore:       configStore,
+			dryRun: dryrun.Reporter{
+				Resource: messagingv1alpha1.SchemeGroupVersion.WithResource("parallels").String(),
+				Stats:    metrics.NewStatsReporter(),
+				Recorder: controller.GetEventRecorder(ctx),
+			},
+		},
+		parallelLister: parallelInformer.Lister(),
+	}
+	impl := controller.NewImpl(c, logger, "AutoTriggerParallels")
+
+	logger.Info("Setting up event handlers")
+
+	parallelInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: isManaged,
+		Handler:    controller.HandleAll(impl.Enqueue),
+	})
+
+	triggerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(messagingv1alpha1.SchemeGroupVersion.WithKind("Parallel")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	// Policies can allow or deny the Trigger of any pipeline, so look at
+	// them all again.
+	policyInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+		impl.GlobalResync(parallelInformer.Informer())
+	}))
+
+	return impl
+}
+
+func isManaged(obj interface{}) bool {
+	if object, ok := obj.(metav1.Object); ok {
+		return resources.IsManaged(object)
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline/resources"
+)
+
+// Membership keeps the pipelines an Addressable declares itself part of in
+// sync with its annotations. The pipeline reconcilers build the rest of each
+// pipeline from the recorded members.
+type Membership struct {
+	EventingClientSet eventingclientset.Interface
+	SequenceLister    messaginglisters.SequenceLister
+	ParallelLister    messaginglisters.ParallelLister
+
+	// DryRun reports the pipelines that are not created or updated in
+	// dry-run mode.
+	DryRun dryrun.Reporter
+}
+
+var managedSelector = labels.SelectorFromSet(labels.Set{resources.ManagedLabel: "true"})
+
+// Reconcile joins the Addressable to the pipelines it declares and removes it
+// from any others. Pass enabled false to remove it from every pipeline.
+func (m *Membership) Reconcile(ctx context.Context, addressable *duckv1.AddressableType, enabled bool) error {
+	var seqName, parName string
+	var seqMember, parMember *resources.Member
+	if enabled {
+		var err error
+		if seqName, seqMember, err = resources.SequenceMembership(addressable); err != nil {
+			return err
+		}
+		if parName, parMember, err = resources.ParallelMembership(addressable); err != nil {
+			return err
+		}
+	}
+
+	if err := m.reconcileSequences(ctx, addressable, seqName, seqMember); err != nil {
+		return err
+	}
+	return m.reconcileParallels(ctx, addressable, parName, parMember)
+}
+
+func (m *Membership) reconcileSequences(ctx context.Context, addressable *duckv1.AddressableType, name string, member *resources.Member) error {
+	logger := logging.FromContext(ctx)
+	client := m.EventingClientSet.MessagingV1alpha1().Sequences(addressable.Namespace)
+
+	sequences, err := m.SequenceLister.Sequences(addressable.Namespace).List(managedSelector)
+	if err != nil {
+		return err
+	}
+	for _, s := range sequences {
+		if s.Name == name {
+			continue
+		}
+		s = s.DeepCopy()
+		if changed, err := resources.Leave(s, addressable.UID); err != nil {
+			return err
+		} else if !changed {
+			continue
+		}
+		if dryrun.Enabled(ctx) {
+			m.DryRun.Report(ctx, addressable, metrics.OperationUpdate, "Sequence", s.Namespace, s.Name)
+			continue
+		}
+		if _, err := client.Update(s); err != nil {
+			return err
+		}
+		logger.Infof("removed %q from Sequence %q", addressable.Name, s.Name)
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	s, err := m.SequenceLister.Sequences(addressable.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		s = resources.MakeSequence(addressable.Namespace, name)
+		if _, err := resources.Join(s, addressable, member); err != nil {
+			return err
+		}
+		s.Spec.Steps = resources.MakeSteps(resources.Members{addressable.UID: *member})
+		if dryrun.Enabled(ctx) {
+			m.DryRun.Report(ctx, addressable, metrics.OperationCreate, "Sequence", s.Namespace, s.Name)
+			return nil
+		}
+		if _, err := client.Create(s); err != nil {
+			return err
+		}
+		logger.Infof("created Sequence %q for %q", name, addressable.Name)
+		return nil
+	} else if err != nil {
+		return err
+	} else if !resources.IsManaged(s) {
+		return fmt.Errorf("sequence %q exists and is not managed by autotrigger", name)
+	}
+
+	s = s.DeepCopy()
+	if changed, err := resources.Join(s, addressable, member); err != nil || !changed {
+		return err
+	}
+	if dryrun.Enabled(ctx) {
+		m.DryRun.Report(ctx, addressable, metrics.OperationUpdate, "Sequence", s.Namespace, s.Name)
+		return nil
+	}
+	if _, err := client.Update(s); err != nil {
+		return err
+	}
+	logger.Infof("added %q to Sequence %q", addressable.Name, name)
+	return nil
+}
+
+func (m *Membership) reconcileParallels(ctx context.Context, addressable *duckv1.AddressableType, name string, member *resources.Member) error {
+	logger := logging.FromContext(ctx)
+	client := m.EventingClientSet.MessagingV1alpha1().Parallels(addressable.Namespace)
+
+	parallels, err := m.ParallelLister.Parallels(addressable.Namespace).List(managedSelector)
+	if err != nil {
+		return err
+	}
+	for _, p := range parallels {
+		if p.Name == name {
+			continue
+		}
+		p = p.DeepCopy()
+		if changed, err := resources.Leave(p, addressable.UID); err != nil {
+			return err
+		} else if !changed {
+			continue
+		}
+		if dryrun.Enabled(ctx) {
+			m.DryRun.Report(ctx, addressable, metrics.OperationUpdate, "Parallel", p.Namespace, p.Name)
+			continue
+		}
+		if _, err := client.Update(p); err != nil {
+			return err
+		}
+		logger.Infof("removed %q from Parallel %q", addressable.Name, p.Name)
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	p, err := m.ParallelLister.Parallels(addressable.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		p = resources.MakeParallel(addressable.Namespace, name)
+		if _, err := resources.Join(p, addressable, member); err != nil {
+			return err
+		}
+		p.Spec.Branches = resources.MakeBranches(resources.Members{addressable.UID: *member})
+		if dryrun.Enabled(ctx) {
+			m.DryRun.Report(ctx, addressable, metrics.OperationCreate, "Parallel", p.Namespace, p.Name)
+			return nil
+		}
+		if _, err := client.Create(p); err != nil {
+			return err
+		}
+		logger.Infof("created Parallel %q for %q", name, addressable.Name)
+		return nil
+	} else if err != nil {
+		return err
+	} else if !resources.IsManaged(p) {
+		return fmt.Errorf("parallel %q exists and is not managed by autotrigger", name)
+	}
+
+	p = p.DeepCopy()
+	if changed, err := resources.Join(p, addressable, member); err != nil || !changed {
+		return err
+	}
+	if dryrun.Enabled(ctx) {
+		m.DryRun.Report(ctx, addressable, metrics.OperationUpdate, "Parallel", p.Namespace, p.Name)
+		return nil
+	}
+	if _, err := client.Update(p); err != nil {
+		return err
+	}
+	logger.Infof("added %q to Parallel %q", addressable.Name, name)
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1alpha1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline/resources"
+)
+
+// ParallelReconciler implements controller.Reconciler for Parallels built by
+// autotrigger.
+type ParallelReconciler struct {
+	triggerReconciler
+
+	parallelLister messaginglisters.ParallelLister
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ controller.Reconciler = (*ParallelReconciler)(nil)
+
+// Reconcile
+func (c *ParallelReconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorf("invalid resource key: %s", key)
+		return nil
+	}
+
+	if c.configStore != nil {
+		ctx = c.configStore.ToContext(ctx)
+	}
+
+	original, err := c.parallelLister.Parallels(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		// The resource may no longer exist, in which case we stop processing.
+		return nil
+	} else if err != nil {
+		return err
+	} else if !resources.IsManaged(original) || original.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	// Don't modify the informers copy
+	return c.reconcile(ctx, original.DeepCopy())
+}
+
+func (c *ParallelReconciler) reconcile(ctx context.Context, parallel *messagingv1alpha1.Parallel) error {
+	logger := logging.FromContext(ctx)
+	client := c.eventingClientSet.MessagingV1alpha1().Parallels(parallel.Namespace)
+
+	members, err := resources.GetMembers(parallel)
+	if err != nil {
+		return err
+	}
+	pruned := members.Prune(parallel)
+	if len(members) == 0 {
+		if dryrun.Enabled(ctx) {
+			c.dryRun.Report(ctx, parallel, metrics.OperationDelete, "Parallel", parallel.Namespace, parallel.Name)
+			return nil
+		}
+		logger.Infof("deleting Parallel %q, it has no members left", parallel.Name)
+		return client.Delete(parallel.Name, &metav1.DeleteOptions{})
+	}
+
+	branches := resources.MakeBranches(members)
+	if pruned || !equality.Semantic.DeepEqual(branches, parallel.Spec.Branches) {
+		if err := resources.SetMembers(parallel, members); err != nil {
+			return err
+		}
+		parallel.Spec.Branches = branches
+		if dryrun.Enabled(ctx) {
+			c.dryRun.Report(ctx, parallel, metrics.OperationUpdate, "Parallel", parallel.Namespace, parallel.Name)
+		} else if parallel, err = client.Update(parallel); err != nil {
+			return err
+		}
+	}
+
+	return c.reconcileTrigger(ctx, parallel, messagingv1alpha1.SchemeGroupVersion.WithKind("Parallel"), members)
+}
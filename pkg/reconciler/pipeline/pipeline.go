@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pipeline builds Sequences and Parallels from the Addressables that
+// declare themselves members of them, and the Triggers feeding them.
+package pipeline
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+	"knative.dev/pkg/logging"
+
+	autotriggerlisters "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	atresources "github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline/resources"
+)
+
+// triggerReconciler keeps the single Trigger feeding a pipeline in sync.
+type triggerReconciler struct {
+	eventingClientSet eventingclientset.Interface
+	triggerLister     eventinglisters.TriggerLister
+	policyLister      autotriggerlisters.AutoTriggerPolicyLister
+	namespaceLister   corev1listers.NamespaceLister
+	recorder          record.EventRecorder
+
+	configStore *config.Store
+	// dryRun reports the writes that are not made in dry-run mode.
+	dryRun dryrun.Reporter
+}
+
+// pipelineObject is a Sequence or a Parallel.
+type pipelineObject interface {
+	metav1.Object
+	runtime.Object
+}
+
+func (r *triggerReconciler) reconcileTrigger(ctx context.Context, pipeline pipelineObject, gvk schema.GroupVersionKind, members resources.Members) error {
+	logger := logging.FromContext(ctx)
+	client := r.eventingClientSet.EventingV1alpha1().Triggers(pipeline.GetNamespace())
+
+	desired := resources.MakeTrigger(pipeline, gvk, members)
+	allowed, err := r.allowed(ctx, pipeline, desired)
+	if err != nil {
+		return err
+	} else if !allowed {
+		// A denied Trigger is not made, and the one feeding the pipeline is
+		// deleted.
+		desired = nil
+	}
+
+	triggers, err := r.triggerLister.Triggers(pipeline.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	var current *eventingv1alpha1.Trigger
+	for _, t := range triggers {
+		if !metav1.IsControlledBy(t, pipeline) {
+			continue
+		}
+		if desired != nil && t.Name == desired.Name && current == nil {
+			current = t
+			continue
+		}
+		// Only one Trigger feeds a pipeline. The Broker of a Trigger cannot
+		// be changed, so the ones on another Broker are deleted.
+		if dryrun.Enabled(ctx) {
+			r.dryRun.Report(ctx, pipeline, metrics.OperationDelete, "Trigger", t.Namespace, t.Name)
+			continue
+		}
+		if err := client.Delete(t.Name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			logger.Errorf("failed to delete Trigger %q: %v", t.Name, err)
+			return err
+		}
+	}
+
+	if desired == nil {
+		return nil
+	}
+
+	if current == nil {
+		if dryrun.Enabled(ctx) {
+			r.dryRun.Report(ctx, pipeline, metrics.OperationCreate, "Trigger", desired.Namespace, desired.Name)
+			return nil
+		}
+		// The Trigger is named after the pipeline and its Broker, so it
+		// already existing means the informer has not seen it yet.
+		if _, err := client.Create(desired); err != nil && !apierrs.IsAlreadyExists(err) {
+			logger.Errorf("failed to create Trigger for %q: %v", pipeline.GetName(), err)
+			return err
+		}
+		return nil
+	}
+
+	if resources.TriggerUpToDate(desired, current) {
+		return nil
+	}
+	current = current.DeepCopy()
+	current.Spec.Filter = desired.Spec.Filter
+	current.Spec.Subscriber = desired.Spec.Subscriber
+	if current.Labels == nil {
+		current.Labels = make(map[string]string, len(desired.Labels))
+	}
+	for k, v := range desired.Labels {
+		current.Labels[k] = v
+	}
+	if dryrun.Enabled(ctx) {
+		r.dryRun.Report(ctx, pipeline, metrics.OperationUpdate, "Trigger", current.Namespace, current.Name)
+		return nil
+	}
+	_, err = client.Update(current)
+	return err
+}
+
+// allowed reports whether every AutoTriggerPolicy selecting the namespace of
+// the pipeline allows the Trigger, reporting a denial as an event on the
+// pipeline, as the autotrigger reconciler does on an Addressable.
+func (r *triggerReconciler) allowed(ctx context.Context, pipeline pipelineObject, trigger *eventingv1alpha1.Trigger) (bool, error) {
+	logger := logging.FromContext(ctx)
+
+	policies, err := r.policyLister.List(labels.Everything())
+	if err != nil {
+		return false, err
+	} else if len(policies) == 0 {
+		return true, nil
+	}
+	namespace, err := r.namespaceLister.Get(pipeline.GetNamespace())
+	if err != nil {
+		return false, err
+	}
+
+	_, denials, invalid := atresources.ApplyPolicies(policies, namespace, []*eventingv1alpha1.Trigger{trigger})
+	for _, policy := range invalid {
+		logger.Infof("%s/%s: %s", pipeline.GetNamespace(), pipeline.GetName(), policy)
+		if r.recorder != nil {
+			r.recorder.Eventf(policy.Policy, corev1.EventTypeWarning, "InvalidNamespaceSelector", "Invalid namespaceSelector: %v", policy.Err)
+		}
+	}
+	for _, denial := range denials {
+		logger.Infof("%s/%s: %s", pipeline.GetNamespace(), pipeline.GetName(), denial)
+		if r.recorder != nil {
+			r.recorder.Event(pipeline, corev1.EventTypeWarning, "TriggerDenied", denial.String())
+		}
+	}
+	return len(denials) == 0, nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/n3wscott/autotrigger/pkg/autotrigger"
+)
+
+const (
+	sequenceAnnotation = "pipeline.eventing.knative.dev/sequence"
+	parallelAnnotation = "pipeline.eventing.knative.dev/parallel"
+	membersAnnotation  = "pipeline.eventing.knative.dev/members"
+
+	// ManagedLabel marks the Sequences and Parallels built by autotrigger.
+	ManagedLabel = "pipeline.eventing.knative.dev/managed"
+)
+
+// Member records an Addressable taking part in a pipeline.
+type Member struct {
+	// Ref points to the member Addressable.
+	Ref corev1.ObjectReference `json:"ref"`
+
+	// Step orders the member within a Sequence.
+	Step int `json:"step,omitempty"`
+
+	// Filter is the Addressable guarding the member's branch of a Parallel.
+	Filter *corev1.ObjectReference `json:"filter,omitempty"`
+
+	// Trigger is the broker filter for the Trigger feeding the pipeline.
+	Trigger *autotrigger.Filter `json:"trigger,omitempty"`
+}
+
+// Members is the set of members of a pipeline, keyed by member UID.
+type Members map[types.UID]Member
+
+// membership is the form of the sequence and parallel annotations.
+type membership struct {
+	Name    string                  `json:"name"`
+	Step    int                     `json:"step,omitempty"`
+	Filter  *corev1.ObjectReference `json:"filter,omitempty"`
+	Trigger *autotrigger.Filter     `json:"trigger,omitempty"`
+}
+
+// SequenceMembership returns the name of the Sequence the Addressable declares
+// itself a step of, and its Member record. The name is empty if it declares
+// none.
+func SequenceMembership(a *duckv1.AddressableType) (string, *Member, error) {
+	return parseMembership(a, sequenceAnnotation)
+}
+
+// ParallelMembership returns the name of the Parallel the Addressable declares
+// itself a branch of, and its Member record. The name is empty if it declares
+// none.
+func ParallelMembership(a *duckv1.AddressableType) (string, *Member, error) {
+	return parseMembership(a, parallelAnnotation)
+}
+
+func parseMembership(a *duckv1.AddressableType, annotation string) (string, *Member, error) {
+	raw, ok := a.Annotations[annotation]
+	if !ok || raw == "" {
+		return "", nil, nil
+	}
+
+	var m membership
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return "", nil, fmt.Errorf("failed to extract %s: %s", annotation, err.Error())
+	}
+	if m.Name == "" {
+		return "", nil, fmt.Errorf("failed to extract %s: %s", annotation, errors.New("missing field: name"))
+	}
+	if m.Trigger != nil {
+		if err := m.Trigger.Validate(); err != nil {
+			return "", nil, fmt.Errorf("failed to extract %s: trigger: %v", annotation, err)
+		}
+		if namespace, _ := m.Trigger.BrokerRef(a.Namespace); namespace != a.Namespace {
+			return "", nil, fmt.Errorf("failed to extract %s: trigger: broker %q is not in namespace %q of the pipeline", annotation, m.Trigger.Broker, a.Namespace)
+		}
+	}
+	if m.Filter != nil && m.Filter.Namespace == "" {
+		m.Filter.Namespace = a.Namespace
+	}
+
+	return m.Name, &Member{
+		Ref: corev1.ObjectReference{
+			APIVersion: a.APIVersion,
+			Kind:       a.Kind,
+			Name:       a.Name,
+		},
+		Step:    m.Step,
+		Filter:  m.Filter,
+		Trigger: m.Trigger,
+	}, nil
+}
+
+// GetMembers reads the members recorded on a pipeline.
+func GetMembers(pipeline metav1.Object) (Members, error) {
+	members := make(Members)
+	raw, ok := pipeline.GetAnnotations()[membersAnnotation]
+	if !ok || raw == "" {
+		return members, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &members); err != nil {
+		return nil, fmt.Errorf("failed to extract pipeline members: %s", err.Error())
+	}
+	return members, nil
+}
+
+// SetMembers records the members of a pipeline.
+func SetMembers(pipeline metav1.Object, members Members) error {
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	annotations := pipeline.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[membersAnnotation] = string(raw)
+	pipeline.SetAnnotations(annotations)
+	return nil
+}
+
+// Sorted returns the members ordered by step, then kind and name.
+func (m Members) Sorted() []Member {
+	sorted := make([]Member, 0, len(m))
+	for _, member := range m {
+		sorted = append(sorted, member)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Step != sorted[j].Step {
+			return sorted[i].Step < sorted[j].Step
+		}
+		if sorted[i].Ref.Kind != sorted[j].Ref.Kind {
+			return sorted[i].Ref.Kind < sorted[j].Ref.Kind
+		}
+		return sorted[i].Ref.Name < sorted[j].Ref.Name
+	})
+	return sorted
+}
+
+// Prune removes the members that are no longer owners of the pipeline, which
+// happens when the garbage collector cleans up after a deleted member.
+func (m Members) Prune(pipeline metav1.Object) bool {
+	owners := make(map[types.UID]bool, len(pipeline.GetOwnerReferences()))
+	for _, ref := range pipeline.GetOwnerReferences() {
+		owners[ref.UID] = true
+	}
+	pruned := false
+	for uid := range m {
+		if !owners[uid] {
+			delete(m, uid)
+			pruned = true
+		}
+	}
+	return pruned
+}
+
+// Join records the Addressable as a member of the pipeline and as one of its
+// owners. It returns true if the pipeline changed.
+func Join(pipeline metav1.Object, a *duckv1.AddressableType, member *Member) (bool, error) {
+	members, err := GetMembers(pipeline)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if existing, ok := members[a.UID]; !ok || !equality.Semantic.DeepEqual(existing, *member) {
+		members[a.UID] = *member
+		if err := SetMembers(pipeline, members); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	for _, ref := range pipeline.GetOwnerReferences() {
+		if ref.UID == a.UID {
+			return changed, nil
+		}
+	}
+	pipeline.SetOwnerReferences(append(pipeline.GetOwnerReferences(), metav1.OwnerReference{
+		APIVersion: a.APIVersion,
+		Kind:       a.Kind,
+		Name:       a.Name,
+		UID:        a.UID,
+	}))
+	return true, nil
+}
+
+// Leave removes the Addressable with the given UID from the members and
+// owners of the pipeline. It returns true if the pipeline changed.
+func Leave(pipeline metav1.Object, uid types.UID) (bool, error) {
+	members, err := GetMembers(pipeline)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if _, ok := members[uid]; ok {
+		delete(members, uid)
+		if err := SetMembers(pipeline, members); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	owners := []metav1.OwnerReference(nil)
+	for _, ref := range pipeline.GetOwnerReferences() {
+		if ref.UID == uid {
+			changed = true
+			continue
+		}
+		owners = append(owners, ref)
+	}
+	pipeline.SetOwnerReferences(owners)
+	return changed, nil
+}
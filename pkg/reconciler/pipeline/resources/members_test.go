@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func addressable(name, step string) *duckv1.AddressableType {
+	return &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			UID:         types.UID(name + "-uid"),
+			Annotations: map[string]string{sequenceAnnotation: `{"name":"flow","step":` + step + `}`},
+		},
+	}
+}
+
+func TestJoinAndLeave(t *testing.T) {
+	s := MakeSequence("default", "flow")
+
+	for _, a := range []*duckv1.AddressableType{addressable("second", "2"), addressable("first", "1")} {
+		name, member, err := SequenceMembership(a)
+		if err != nil {
+			t.Fatalf("SequenceMembership() = %v", err)
+		}
+		if name != "flow" {
+			t.Errorf("SequenceMembership() name = %q, wanted %q", name, "flow")
+		}
+		if changed, err := Join(s, a, member); err != nil || !changed {
+			t.Fatalf("Join() = %v, %v, wanted true, nil", changed, err)
+		}
+		if changed, err := Join(s, a, member); err != nil || changed {
+			t.Fatalf("second Join() = %v, %v, wanted false, nil", changed, err)
+		}
+	}
+
+	members, err := GetMembers(s)
+	if err != nil {
+		t.Fatalf("GetMembers() = %v", err)
+	}
+	var got []string
+	for _, step := range MakeSteps(members) {
+		got = append(got, step.Ref.Name)
+	}
+	if diff := cmp.Diff([]string{"first", "second"}, got); diff != "" {
+		t.Errorf("MakeSteps (-want, +got) = %v", diff)
+	}
+
+	if changed, err := Leave(s, "first-uid"); err != nil || !changed {
+		t.Fatalf("Leave() = %v, %v, wanted true, nil", changed, err)
+	}
+	if len(s.OwnerReferences) != 1 || s.OwnerReferences[0].UID != "second-uid" {
+		t.Errorf("OwnerReferences = %v, wanted only second-uid", s.OwnerReferences)
+	}
+
+	// Simulate the garbage collector removing the last owner.
+	s.OwnerReferences = nil
+	members, _ = GetMembers(s)
+	if !members.Prune(s) || len(members) != 0 {
+		t.Errorf("Prune() left %v, wanted no members", members)
+	}
+}
+
+func TestMembershipTrigger(t *testing.T) {
+	a := addressable("first", "1")
+	a.Annotations[sequenceAnnotation] = `{"name":"flow","step":1,"trigger":{"broker":"shared","type":"dev.example.order.created"}}`
+	_, member, err := SequenceMembership(a)
+	if err != nil {
+		t.Fatalf("SequenceMembership() = %v", err)
+	}
+	trigger := MakeTrigger(MakeSequence("default", "flow"), messagingv1alpha1.SchemeGroupVersion.WithKind("Sequence"), Members{a.UID: *member})
+	if got, want := trigger.Spec.Broker, "shared"; got != want {
+		t.Errorf("MakeTrigger() broker = %q, wanted %q", got, want)
+	}
+	if diff := cmp.Diff(map[string]string{"type": "dev.example.order.created"}, map[string]string(*trigger.Spec.Filter.Attributes)); diff != "" {
+		t.Errorf("MakeTrigger() attributes (-want, +got) = %v", diff)
+	}
+
+	a.Annotations[sequenceAnnotation] = `{"name":"flow","step":1,"trigger":{"tpye":"dev.example.order.created"}}`
+	if _, _, err := SequenceMembership(a); err == nil {
+		t.Error("SequenceMembership() with an unknown trigger key = nil, wanted an error")
+	}
+
+	a.Annotations[sequenceAnnotation] = `{"name":"flow","step":1,"trigger":{"broker":"events/shared"}}`
+	if _, _, err := SequenceMembership(a); err == nil {
+		t.Error("SequenceMembership() with a broker in another namespace = nil, wanted an error")
+	}
+}
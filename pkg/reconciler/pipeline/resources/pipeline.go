@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	"knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	defaultBroker = "default"
+)
+
+// IsManaged returns true if the pipeline was built by autotrigger.
+func IsManaged(pipeline metav1.Object) bool {
+	return pipeline.GetLabels()[ManagedLabel] == "true"
+}
+
+// MakeSequence creates an empty Sequence to be filled in by its members.
+func MakeSequence(namespace, name string) *messagingv1alpha1.Sequence {
+	return &messagingv1alpha1.Sequence{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{ManagedLabel: "true"},
+		},
+	}
+}
+
+// MakeParallel creates an empty Parallel to be filled in by its members.
+func MakeParallel(namespace, name string) *messagingv1alpha1.Parallel {
+	return &messagingv1alpha1.Parallel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{ManagedLabel: "true"},
+		},
+	}
+}
+
+// MakeSteps creates the steps of a Sequence from its members.
+func MakeSteps(members Members) []v1alpha1.Destination {
+	steps := make([]v1alpha1.Destination, 0, len(members))
+	for _, m := range members.Sorted() {
+		ref := m.Ref
+		steps = append(steps, v1alpha1.Destination{Ref: &ref})
+	}
+	return steps
+}
+
+// MakeBranches creates the branches of a Parallel from its members.
+func MakeBranches(members Members) []messagingv1alpha1.ParallelBranch {
+	branches := make([]messagingv1alpha1.ParallelBranch, 0, len(members))
+	for _, m := range members.Sorted() {
+		ref := m.Ref
+		branch := messagingv1alpha1.ParallelBranch{
+			Subscriber: v1alpha1.Destination{Ref: &ref},
+		}
+		if m.Filter != nil {
+			filter := *m.Filter
+			branch.Filter = &v1alpha1.Destination{Ref: &filter}
+		}
+		branches = append(branches, branch)
+	}
+	return branches
+}
+
+// MakeTrigger creates the Trigger feeding a pipeline. The broker filter is
+// taken from the first member that declares one; without one the pipeline
+// receives all events from the default Broker.
+func MakeTrigger(pipeline metav1.Object, gvk schema.GroupVersionKind, members Members) *eventingv1alpha1.Trigger {
+	broker := defaultBroker
+	attributes := make(eventingv1alpha1.TriggerFilterAttributes)
+	for _, m := range members.Sorted() {
+		if m.Trigger == nil {
+			continue
+		}
+		// Members name a Broker in the namespace of the pipeline only.
+		_, broker = m.Trigger.BrokerRef(pipeline.GetNamespace())
+		attributes = m.Trigger.Attributes()
+		break
+	}
+
+	return &eventingv1alpha1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TriggerName(pipeline, gvk, broker),
+			Namespace: pipeline.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pipeline, gvk),
+			},
+			Labels: map[string]string{ManagedLabel: "true"},
+		},
+		Spec: eventingv1alpha1.TriggerSpec{
+			Broker: broker,
+			Filter: &eventingv1alpha1.TriggerFilter{
+				Attributes: &attributes,
+			},
+			Subscriber: &v1alpha1.Destination{
+				Ref: &corev1.ObjectReference{
+					APIVersion: gvk.GroupVersion().String(),
+					Kind:       gvk.Kind,
+					Name:       pipeline.GetName(),
+				},
+			},
+		},
+	}
+}
+
+// TriggerName names the Trigger feeding a pipeline after the pipeline and its
+// Broker. Creating it twice then fails rather than making a duplicate, and
+// since the Broker of a Trigger cannot be changed, moving the pipeline to
+// another Broker makes a new Trigger.
+func TriggerName(pipeline metav1.Object, gvk schema.GroupVersionKind, broker string) string {
+	return kmeta.ChildName(pipeline.GetName()+"-"+strings.ToLower(gvk.Kind)+"-", broker)
+}
+
+// TriggerUpToDate reports whether the Trigger has the filter, subscriber and
+// labels of the desired one. The fields set by the defaulting webhook, such
+// as the namespace of the subscriber, are left out.
+func TriggerUpToDate(desired, trigger *eventingv1alpha1.Trigger) bool {
+	if trigger.Spec.Broker != desired.Spec.Broker ||
+		!equality.Semantic.DeepEqual(filterAttributes(desired), filterAttributes(trigger)) {
+		return false
+	}
+	want, got := desired.Spec.Subscriber, trigger.Spec.Subscriber
+	if got == nil || got.Ref == nil ||
+		got.Ref.APIVersion != want.Ref.APIVersion || got.Ref.Kind != want.Ref.Kind || got.Ref.Name != want.Ref.Name {
+		return false
+	}
+	for k, v := range desired.Labels {
+		if trigger.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// filterAttributes returns the attributes of the filter of the Trigger, empty
+// when it has none.
+func filterAttributes(t *eventingv1alpha1.Trigger) map[string]string {
+	attributes := make(map[string]string)
+	if t.Spec.Filter != nil && t.Spec.Filter.Attributes != nil {
+		for k, v := range *t.Spec.Filter.Attributes {
+			attributes[k] = v
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1alpha1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline/resources"
+)
+
+// SequenceReconciler implements controller.Reconciler for Sequences built by
+// autotrigger.
+type SequenceReconciler struct {
+	triggerReconciler
+
+	sequenceLister messaginglisters.SequenceLister
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ controller.Reconciler = (*SequenceReconciler)(nil)
+
+// Reconcile
+func (c *SequenceReconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorf("invalid resource key: %s", key)
+		return nil
+	}
+
+	if c.configStore != nil {
+		ctx = c.configStore.ToContext(ctx)
+	}
+
+	original, err := c.sequenceLister.Sequences(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		// The resource may no longer exist, in which case we stop processing.
+		return nil
+	} else if err != nil {
+		return err
+	} else if !resources.IsManaged(original) || original.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	// Don't modify the informers copy
+	return c.reconcile(ctx, original.DeepCopy())
+}
+
+func (c *SequenceReconciler) reconcile(ctx context.Context, sequence *messagingv1alpha1.Sequence) error {
+	logger := logging.FromContext(ctx)
+	client := c.eventingClientSet.MessagingV1alpha1().Sequences(sequence.Namespace)
+
+	members, err := resources.GetMembers(sequence)
+	if err != nil {
+		return err
+	}
+	pruned := members.Prune(sequence)
+	if len(members) == 0 {
+		if dryrun.Enabled(ctx) {
+			c.dryRun.Report(ctx, sequence, metrics.OperationDelete, "Sequence", sequence.Namespace, sequence.Name)
+			return nil
+		}
+		logger.Infof("deleting Sequence %q, it has no members left", sequence.Name)
+		return client.Delete(sequence.Name, &metav1.DeleteOptions{})
+	}
+
+	steps := resources.MakeSteps(members)
+	if pruned || !equality.Semantic.DeepEqual(steps, sequence.Spec.Steps) {
+		if err := resources.SetMembers(sequence, members); err != nil {
+			return err
+		}
+		sequence.Spec.Steps = steps
+		if dryrun.Enabled(ctx) {
+			c.dryRun.Report(ctx, sequence, metrics.OperationUpdate, "Sequence", sequence.Namespace, sequence.Name)
+		} else if sequence, err = client.Update(sequence); err != nil {
+			return err
+		}
+	}
+
+	return c.reconcileTrigger(ctx, sequence, messagingv1alpha1.SchemeGroupVersion.WithKind("Sequence"), members)
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+
+	"github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/config"
+	atresources "github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/dryrun"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/pipeline/resources"
+	. "github.com/n3wscott/autotrigger/pkg/reconciler/testing"
+)
+
+const testNS = "test-namespace"
+
+var sequenceGVK = messagingv1alpha1.SchemeGroupVersion.WithKind("Sequence")
+
+// newSequence makes the Sequence the Addressable declares itself the first
+// step of, with its members recorded.
+func newSequence(t *testing.T, membership string) (*messagingv1alpha1.Sequence, resources.Members) {
+	a := &duckv1.AddressableType{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "serving.knative.dev/v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   testNS,
+			Name:        "first",
+			UID:         "first-uid",
+			Annotations: map[string]string{"pipeline.eventing.knative.dev/sequence": membership},
+		},
+	}
+	_, member, err := resources.SequenceMembership(a)
+	if err != nil {
+		t.Fatalf("SequenceMembership() = %v", err)
+	}
+	s := resources.MakeSequence(testNS, "flow")
+	s.UID = "flow-uid"
+	if _, err := resources.Join(s, a, member); err != nil {
+		t.Fatalf("Join() = %v", err)
+	}
+	members, err := resources.GetMembers(s)
+	if err != nil {
+		t.Fatalf("GetMembers() = %v", err)
+	}
+	s.Spec.Steps = resources.MakeSteps(members)
+	return s, members
+}
+
+// defaulted sets the fields the eventing webhook defaults on the Trigger.
+func defaulted(trigger *eventingv1alpha1.Trigger) *eventingv1alpha1.Trigger {
+	trigger = trigger.DeepCopy()
+	trigger.Spec.Subscriber.Ref.Namespace = trigger.Namespace
+	trigger.Labels["team"] = "a"
+	return trigger
+}
+
+func dryRun(t *testing.T) context.Context {
+	cfg, err := config.NewAutoTriggerFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.ConfigName},
+		Data:       map[string]string{"dry-run": "true"},
+	})
+	if err != nil {
+		t.Fatalf("NewAutoTriggerFromConfigMap() = %v", err)
+	}
+	return config.ToContext(context.Background(), &config.Config{AutoTrigger: cfg})
+}
+
+// wouldWrite formats the DryRun event of a write that is not made.
+func wouldWrite(operation, kind, name string) string {
+	return Eventf(corev1.EventTypeNormal, "DryRun", "Would %s %s %s/%s", operation, kind, testNS, name)
+}
+
+func TestSequenceReconcile(t *testing.T) {
+	key := testNS + "/flow"
+
+	sequence, members := newSequence(t, `{"name":"flow","step":1,"trigger":{"broker":"shared","type":"dev.example.order.created"}}`)
+	desired := resources.MakeTrigger(sequence, sequenceGVK, members)
+
+	drifted := defaulted(desired)
+	drifted.Spec.Filter = &eventingv1alpha1.TriggerFilter{
+		Attributes: &eventingv1alpha1.TriggerFilterAttributes{"type": "dev.example.order.deleted"},
+	}
+	reverted := drifted.DeepCopy()
+	reverted.Spec.Filter = desired.Spec.Filter
+	reverted.Spec.Subscriber = desired.Spec.Subscriber
+
+	moved, movedMembers := newSequence(t, `{"name":"flow","step":1,"trigger":{"type":"dev.example.order.created"}}`)
+	onDefault := resources.MakeTrigger(moved, sequenceGVK, movedMembers)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNS}}
+	policy := &v1alpha1.AutoTriggerPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-only"},
+		Spec: v1alpha1.AutoTriggerPolicySpec{
+			AllowedBrokers: []string{"default"},
+		},
+	}
+	_, denials, invalid := atresources.ApplyPolicies([]*v1alpha1.AutoTriggerPolicy{policy}, namespace, []*eventingv1alpha1.Trigger{desired})
+	if len(invalid) != 0 || len(denials) != 1 {
+		t.Fatalf("ApplyPolicies() = %v, %v", denials, invalid)
+	}
+
+	stale := sequence.DeepCopy()
+	stale.Spec.Steps = nil
+
+	empty := resources.MakeSequence(testNS, "flow")
+
+	alreadyExists := func(action Action) error {
+		if action.Verb != VerbCreate || action.Resource != "triggers" {
+			return nil
+		}
+		return apierrs.NewAlreadyExists(schema.GroupResource{Group: "eventing.knative.dev", Resource: "triggers"}, desired.Name)
+	}
+
+	table := TableTest{{
+		Name: "bad key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "sequence not found",
+		Key:  key,
+	}, {
+		Name:        "create trigger",
+		Key:         key,
+		Objects:     []runtime.Object{sequence},
+		WantCreates: []runtime.Object{desired},
+	}, {
+		Name:         "trigger already exists",
+		Key:          key,
+		Objects:      []runtime.Object{sequence},
+		WithReactors: []Reactor{alreadyExists},
+		WantCreates:  []runtime.Object{desired},
+	}, {
+		Name:    "up to date, with defaults and labels set by others",
+		Key:     key,
+		Objects: []runtime.Object{sequence, defaulted(desired)},
+	}, {
+		Name:        "filter drifted",
+		Key:         key,
+		Objects:     []runtime.Object{sequence, drifted},
+		WantUpdates: []runtime.Object{reverted},
+	}, {
+		Name:        "broker changed",
+		Key:         key,
+		Objects:     []runtime.Object{moved, defaulted(desired)},
+		WantCreates: []runtime.Object{onDefault},
+		WantDeletes: []Action{{
+			Resource:  "triggers",
+			Namespace: testNS,
+			Name:      desired.Name,
+		}},
+	}, {
+		Name:        "allowed by policy",
+		Key:         key,
+		Objects:     []runtime.Object{moved, namespace, policy},
+		WantCreates: []runtime.Object{onDefault},
+	}, {
+		Name:       "denied by policy",
+		Key:        key,
+		Objects:    []runtime.Object{sequence, namespace, policy},
+		WantEvents: []string{Eventf(corev1.EventTypeWarning, "TriggerDenied", denials[0].String())},
+	}, {
+		Name:       "denied by policy, trigger deleted",
+		Key:        key,
+		Objects:    []runtime.Object{sequence, namespace, policy, defaulted(desired)},
+		WantEvents: []string{Eventf(corev1.EventTypeWarning, "TriggerDenied", denials[0].String())},
+		WantDeletes: []Action{{
+			Resource:  "triggers",
+			Namespace: testNS,
+			Name:      desired.Name,
+		}},
+	}, {
+		Name:       "dry run, create trigger",
+		Ctx:        dryRun(t),
+		Key:        key,
+		Objects:    []runtime.Object{sequence},
+		WantEvents: []string{wouldWrite("create", "Trigger", desired.Name)},
+	}, {
+		Name:       "dry run, filter drifted",
+		Ctx:        dryRun(t),
+		Key:        key,
+		Objects:    []runtime.Object{sequence, drifted},
+		WantEvents: []string{wouldWrite("update", "Trigger", desired.Name)},
+	}, {
+		Name:    "dry run, broker changed",
+		Ctx:     dryRun(t),
+		Key:     key,
+		Objects: []runtime.Object{moved, defaulted(desired)},
+		WantEvents: []string{
+			wouldWrite("delete", "Trigger", desired.Name),
+			wouldWrite("create", "Trigger", onDefault.Name),
+		},
+	}, {
+		Name:    "dry run, steps stale",
+		Ctx:     dryRun(t),
+		Key:     key,
+		Objects: []runtime.Object{stale, defaulted(desired)},
+		WantEvents: []string{
+			wouldWrite("update", "Sequence", "flow"),
+		},
+	}, {
+		Name:       "dry run, no members left",
+		Ctx:        dryRun(t),
+		Key:        key,
+		Objects:    []runtime.Object{empty},
+		WantEvents: []string{wouldWrite("delete", "Sequence", "flow")},
+	}}
+
+	table.Test(t, func(t *testing.T, r *TableRow, f Fakes) controller.Reconciler {
+		return &SequenceReconciler{
+			triggerReconciler: triggerReconciler{
+				eventingClientSet: f.Client,
+				triggerLister:     f.Listers.GetTriggerLister(),
+				policyLister:      f.Listers.GetAutoTriggerPolicyLister(),
+				namespaceLister:   f.Listers.GetNamespaceLister(),
+				recorder:          f.Recorder,
+				dryRun: dryrun.Reporter{
+					Resource: "sequences.v1alpha1.messaging.knative.dev",
+					Stats:    f.Stats,
+					Recorder: f.Recorder,
+				},
+			},
+			sequenceLister: f.Listers.GetSequenceLister(),
+		}
+	})
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventingfake "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+)
+
+// The verbs of the Actions recorded by the Clientset.
+const (
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbDelete = "delete"
+	VerbPatch  = "patch"
+
+	VerbUpdateStatus = "update-status"
+)
+
+// Action is a write made with the Clientset.
+type Action struct {
+	Verb      string
+	Resource  string
+	Namespace string
+	// Name is set for deletes and patches only, the other writes carry the
+	// Object.
+	Name   string
+	Object runtime.Object
+	// Patch is the body of a patch.
+	Patch string
+}
+
+// Reactor is given each Action before it is recorded, and fails it by
+// returning an error.
+type Reactor func(Action) error
+
+// InduceFailure returns a Reactor that fails the writes of the verb to the
+// resource.
+func InduceFailure(verb, resource string) Reactor {
+	return func(action Action) error {
+		if action.Verb != verb || action.Resource != resource {
+			return nil
+		}
+		return fmt.Errorf("inducing failure for %s %s", verb, resource)
+	}
+}
+
+// Clientset is the fake eventing clientset, serving the eventing objects of a
+// test from its object tracker. It records the writes the reconcilers make
+// and passes them to the Reactors before the tracker applies them.
+type Clientset struct {
+	*eventingfake.Clientset
+
+	reactors []Reactor
+
+	lock    sync.Mutex
+	actions []Action
+}
+
+var _ eventingclientset.Interface = (*Clientset)(nil)
+
+// NewClientset makes a Clientset tracking the eventing objects of objs, which
+// passes each write to the reactors. The objects of other types are left to
+// the Listers.
+func NewClientset(objs []runtime.Object, reactors ...Reactor) *Clientset {
+	var tracked []runtime.Object
+	for _, obj := range objs {
+		switch obj.(type) {
+		case *eventingv1alpha1.Trigger, *eventingv1alpha1.EventType,
+			*messagingv1alpha1.Sequence, *messagingv1alpha1.Parallel:
+			tracked = append(tracked, obj)
+		}
+	}
+	c := &Clientset{
+		Clientset: eventingfake.NewSimpleClientset(tracked...),
+		reactors:  reactors,
+	}
+	c.PrependReactor("*", "*", c.react)
+	return c
+}
+
+// Actions returns the writes made so far, including the failed ones.
+func (c *Clientset) Actions() []Action {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]Action(nil), c.actions...)
+}
+
+func (c *Clientset) invoke(action Action) error {
+	c.lock.Lock()
+	c.actions = append(c.actions, action)
+	c.lock.Unlock()
+	for _, reactor := range c.reactors {
+		if err := reactor(action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// react is the first reaction of the fake clientset. It records the writes
+// and fails those a Reactor fails, and leaves the rest to the tracker.
+func (c *Clientset) react(action clientgotesting.Action) (bool, runtime.Object, error) {
+	resource := action.GetResource().Resource
+	namespace := action.GetNamespace()
+	var recorded Action
+	switch a := action.(type) {
+	case clientgotesting.CreateActionImpl:
+		recorded = Action{Verb: VerbCreate, Resource: resource, Namespace: namespace, Object: a.GetObject().DeepCopyObject()}
+	case clientgotesting.UpdateActionImpl:
+		recorded = Action{Verb: VerbUpdate, Resource: resource, Namespace: namespace, Object: a.GetObject().DeepCopyObject()}
+		if a.GetSubresource() == "status" {
+			recorded.Verb = VerbUpdateStatus
+		}
+	case clientgotesting.DeleteActionImpl:
+		recorded = Action{Verb: VerbDelete, Resource: resource, Namespace: namespace, Name: a.GetName()}
+	case clientgotesting.PatchActionImpl:
+		recorded = Action{Verb: VerbPatch, Resource: resource, Namespace: namespace, Name: a.GetName(), Patch: string(a.GetPatch())}
+	default:
+		return false, nil, nil
+	}
+	if err := c.invoke(recorded); err != nil {
+		return true, nil, err
+	}
+	// The tracker does not generate names, and would take the objects named
+	// by the API server for one another.
+	if recorded.Verb == VerbCreate {
+		if m, err := meta.Accessor(recorded.Object); err == nil && m.GetName() == "" && m.GetGenerateName() != "" {
+			return true, recorded.Object, nil
+		}
+	}
+	return false, nil, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Dynamic returns a dynamic client that lists the Addressables of the Listers
+// as any resource, and records the patches and status updates the reconcilers
+// make in the Clientset, rather than sending them to an API server. Any other
+// call panics.
+func (f Fakes) Dynamic() dynamic.Interface {
+	return &dynamicClient{c: f.Client, listers: f.Listers}
+}
+
+type dynamicClient struct {
+	c       *Clientset
+	listers Listers
+}
+
+func (d *dynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &dynamicResource{dynamicClient: d, gvr: gvr}
+}
+
+type dynamicResource struct {
+	dynamic.NamespaceableResourceInterface
+	*dynamicClient
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+func (r *dynamicResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &dynamicResource{dynamicClient: r.dynamicClient, gvr: r.gvr, namespace: namespace}
+}
+
+func (r *dynamicResource) List(opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := r.listers.GetAddressableLister(r.gvr).ByNamespace(r.namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	list := &unstructured.UnstructuredList{}
+	for _, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, unstructured.Unstructured{Object: u})
+	}
+	return list, nil
+}
+
+func (r *dynamicResource) UpdateStatus(obj *unstructured.Unstructured, _ metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	obj = obj.DeepCopy()
+	if err := r.c.invoke(Action{Verb: VerbUpdateStatus, Resource: r.gvr.Resource, Namespace: r.namespace, Object: obj}); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (r *dynamicResource) Patch(name string, _ types.PatchType, data []byte, _ metav1.PatchOptions, _ ...string) (*unstructured.Unstructured, error) {
+	if err := r.c.invoke(Action{Verb: VerbPatch, Resource: r.gvr.Resource, Namespace: r.namespace, Name: name, Patch: string(data)}); err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{}, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/n3wscott/autotrigger/pkg/metrics"
+	"github.com/n3wscott/autotrigger/pkg/reconciler"
+)
+
+// AddressableInfo is a reconciler.AddressableInfo reporting the kinds in it as
+// Addressable.
+type AddressableInfo []schema.GroupVersionKind
+
+var _ reconciler.AddressableInfo = AddressableInfo(nil)
+
+// IsGVKAddressable implements reconciler.AddressableInfo.
+func (a AddressableInfo) IsGVKAddressable(_ context.Context, gvk schema.GroupVersionKind) bool {
+	for _, kind := range a {
+		if kind == gvk {
+			return true
+		}
+	}
+	return false
+}
+
+// FakeStatsReporter is a metrics.StatsReporter that counts what is reported
+// to it.
+type FakeStatsReporter struct {
+	lock sync.Mutex
+
+	Reconciles          int
+	ReconcileErrors     int
+	TriggerOperations   map[string]int
+	DryRunOperations    map[string]int
+	FilterParseFailures int
+	WatchedResources    int
+}
+
+var _ metrics.StatsReporter = (*FakeStatsReporter)(nil)
+
+// ReportReconcile implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportReconcile(_ string, _ time.Duration, err error) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.Reconciles++
+	if err != nil {
+		r.ReconcileErrors++
+	}
+	return nil
+}
+
+// ReportRequeueDelay implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportRequeueDelay(string, time.Duration) error {
+	return nil
+}
+
+// ReportWriteBudgetWait implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportWriteBudgetWait(string, time.Duration) error {
+	return nil
+}
+
+// ReportTriggerOperation implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportTriggerOperation(_, operation string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.TriggerOperations == nil {
+		r.TriggerOperations = make(map[string]int)
+	}
+	r.TriggerOperations[operation]++
+	return nil
+}
+
+// ReportDryRunOperation implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportDryRunOperation(_, operation string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.DryRunOperations == nil {
+		r.DryRunOperations = make(map[string]int)
+	}
+	r.DryRunOperations[operation]++
+	return nil
+}
+
+// ReportFilterParseFailure implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportFilterParseFailure(string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.FilterParseFailures++
+	return nil
+}
+
+// ReportWatchedResources implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportWatchedResources(count int) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.WatchedResources = count
+	return nil
+}
+
+// ReportTriggers implements metrics.StatsReporter.
+func (r *FakeStatsReporter) ReportTriggers(string, int, int) error {
+	return nil
+}
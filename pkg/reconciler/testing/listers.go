@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing holds fakes of the clients and listers the reconcilers use,
+// and a table driven test runner for them, in the style of
+// knative.dev/pkg/reconciler/testing.
+package testing
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	messagingv1alpha1 "knative.dev/eventing/pkg/apis/messaging/v1alpha1"
+	eventinglisters "knative.dev/eventing/pkg/client/listers/eventing/v1alpha1"
+	messaginglisters "knative.dev/eventing/pkg/client/listers/messaging/v1alpha1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	autotriggerv1alpha1 "github.com/n3wscott/autotrigger/pkg/apis/autotrigger/v1alpha1"
+	autotriggerlisters "github.com/n3wscott/autotrigger/pkg/client/listers/autotrigger/v1alpha1"
+	"github.com/n3wscott/autotrigger/pkg/reconciler/autotrigger/resources"
+)
+
+// Listers serves a fixed set of objects from the listers the reconcilers use,
+// as the informers would once synced.
+type Listers struct {
+	addressables cache.Indexer
+	sources      cache.Indexer
+	triggers     cache.Indexer
+	eventTypes   cache.Indexer
+	sequences    cache.Indexer
+	parallels    cache.Indexer
+	profiles     cache.Indexer
+	bindings     cache.Indexer
+	policies     cache.Indexer
+	namespaces   cache.Indexer
+	crds         cache.Indexer
+}
+
+// NewListers makes Listers serving the objects. It panics on an object of a
+// type no lister serves.
+func NewListers(objs []runtime.Object) Listers {
+	l := Listers{
+		addressables: newIndexer(nil),
+		sources:      newIndexer(nil),
+		// The autotrigger reconciler looks up Triggers by owner.
+		triggers:   newIndexer(cache.Indexers{resources.OwnerUIDIndex: resources.OwnerUIDIndexFunc}),
+		eventTypes: newIndexer(nil),
+		sequences:  newIndexer(nil),
+		parallels:  newIndexer(nil),
+		profiles:   newIndexer(nil),
+		bindings:   newIndexer(nil),
+		policies:   newIndexer(nil),
+		namespaces: newIndexer(nil),
+		crds:       newIndexer(nil),
+	}
+	for _, obj := range objs {
+		var indexer cache.Indexer
+		switch obj.(type) {
+		case *duckv1.AddressableType:
+			indexer = l.addressables
+		case *duckv1.Source:
+			indexer = l.sources
+		case *eventingv1alpha1.Trigger:
+			indexer = l.triggers
+		case *eventingv1alpha1.EventType:
+			indexer = l.eventTypes
+		case *messagingv1alpha1.Sequence:
+			indexer = l.sequences
+		case *messagingv1alpha1.Parallel:
+			indexer = l.parallels
+		case *autotriggerv1alpha1.AutoTriggerProfile:
+			indexer = l.profiles
+		case *autotriggerv1alpha1.AutoTriggerBinding:
+			indexer = l.bindings
+		case *autotriggerv1alpha1.AutoTriggerPolicy:
+			indexer = l.policies
+		case *corev1.Namespace:
+			indexer = l.namespaces
+		case *apiextensionsv1beta1.CustomResourceDefinition:
+			indexer = l.crds
+		default:
+			panic(fmt.Sprintf("no lister for objects of type %T", obj))
+		}
+		if err := indexer.Add(obj); err != nil {
+			panic(err)
+		}
+	}
+	return l
+}
+
+func newIndexer(indexers cache.Indexers) cache.Indexer {
+	all := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+	for name, index := range indexers {
+		all[name] = index
+	}
+	return cache.NewIndexer(cache.MetaNamespaceKeyFunc, all)
+}
+
+// GetAddressableLister returns a lister of the Addressables, which are all
+// served as the resource gvr.
+func (l Listers) GetAddressableLister(gvr schema.GroupVersionResource) cache.GenericLister {
+	return cache.NewGenericLister(l.addressables, gvr.GroupResource())
+}
+
+// GetSourceLister returns a lister of the Sources, which are all served as
+// the resource gvr.
+func (l Listers) GetSourceLister(gvr schema.GroupVersionResource) cache.GenericLister {
+	return cache.NewGenericLister(l.sources, gvr.GroupResource())
+}
+
+// GetTriggerLister returns a lister of the Triggers.
+func (l Listers) GetTriggerLister() eventinglisters.TriggerLister {
+	return eventinglisters.NewTriggerLister(l.triggers)
+}
+
+// GetTriggerIndexer returns the indexer of the Triggers, with the
+// resources.OwnerUIDIndex.
+func (l Listers) GetTriggerIndexer() cache.Indexer {
+	return l.triggers
+}
+
+// GetEventTypeLister returns a lister of the EventTypes.
+func (l Listers) GetEventTypeLister() eventinglisters.EventTypeLister {
+	return eventinglisters.NewEventTypeLister(l.eventTypes)
+}
+
+// GetSequenceLister returns a lister of the Sequences.
+func (l Listers) GetSequenceLister() messaginglisters.SequenceLister {
+	return messaginglisters.NewSequenceLister(l.sequences)
+}
+
+// GetParallelLister returns a lister of the Parallels.
+func (l Listers) GetParallelLister() messaginglisters.ParallelLister {
+	return messaginglisters.NewParallelLister(l.parallels)
+}
+
+// GetAutoTriggerProfileLister returns a lister of the AutoTriggerProfiles.
+func (l Listers) GetAutoTriggerProfileLister() autotriggerlisters.AutoTriggerProfileLister {
+	return autotriggerlisters.NewAutoTriggerProfileLister(l.profiles)
+}
+
+// GetAutoTriggerBindingLister returns a lister of the AutoTriggerBindings.
+func (l Listers) GetAutoTriggerBindingLister() autotriggerlisters.AutoTriggerBindingLister {
+	return autotriggerlisters.NewAutoTriggerBindingLister(l.bindings)
+}
+
+// GetAutoTriggerPolicyLister returns a lister of the AutoTriggerPolicies.
+func (l Listers) GetAutoTriggerPolicyLister() autotriggerlisters.AutoTriggerPolicyLister {
+	return autotriggerlisters.NewAutoTriggerPolicyLister(l.policies)
+}
+
+// GetNamespaceLister returns a lister of the Namespaces.
+func (l Listers) GetNamespaceLister() corev1listers.NamespaceLister {
+	return corev1listers.NewNamespaceLister(l.namespaces)
+}
+
+// GetCustomResourceDefinitionLister returns a lister of the
+// CustomResourceDefinitions.
+func (l Listers) GetCustomResourceDefinitionLister() apiextensionslisters.CustomResourceDefinitionLister {
+	return apiextensionslisters.NewCustomResourceDefinitionLister(l.crds)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// eventBufferSize is the number of events a row may record.
+const eventBufferSize = 20
+
+// Fakes are what a Factory builds the Reconciler under test from.
+type Fakes struct {
+	Listers  Listers
+	Client   *Clientset
+	Recorder record.EventRecorder
+	Stats    *FakeStatsReporter
+}
+
+// Factory makes the Reconciler to test a row with.
+type Factory func(*testing.T, *TableRow, Fakes) controller.Reconciler
+
+// TableRow holds a single row of a TableTest: the objects the listers serve,
+// the key to reconcile and the writes and events it is expected to lead to.
+type TableRow struct {
+	// Name is the name of the subtest.
+	Name string
+
+	// Ctx is the context to reconcile with, if not the background one.
+	Ctx context.Context
+
+	// Objects are served by the listers, and the eventing ones by the
+	// clientset too.
+	Objects []runtime.Object
+
+	// Key is the key to reconcile.
+	Key string
+
+	// WithReactors are given the writes of the Reconciler.
+	WithReactors []Reactor
+
+	// WantErr is whether the Reconciler returns an error.
+	WantErr bool
+
+	// WantCreates are the objects expected to be created, in order.
+	WantCreates []runtime.Object
+
+	// WantUpdates are the objects expected to be updated, in order.
+	WantUpdates []runtime.Object
+
+	// WantStatusUpdates are the objects expected to have their status
+	// updated, in order. They are compared in their unstructured form, as
+	// the status of a resource may be updated with the dynamic client.
+	WantStatusUpdates []runtime.Object
+
+	// WantDeletes are the deletes expected, in order. Only their Resource,
+	// Namespace and Name are compared.
+	WantDeletes []Action
+
+	// WantPatches are the patches expected, in order. Only their Resource,
+	// Namespace, Name and Patch are compared.
+	WantPatches []Action
+
+	// WantEvents are the events expected, as formatted by
+	// record.FakeRecorder.
+	WantEvents []string
+
+	// OtherTestData is for the Factory and PostConditions of a test.
+	OtherTestData map[string]interface{}
+
+	// PostConditions are run once the row is checked.
+	PostConditions []func(*testing.T, *TableRow, Fakes)
+}
+
+// TableTest is a list of rows run as subtests.
+type TableTest []TableRow
+
+// Test runs every row, with a Reconciler from the factory.
+func (tt TableTest) Test(t *testing.T, factory Factory) {
+	for _, row := range tt {
+		row := row
+		t.Run(row.Name, func(t *testing.T) {
+			row.Test(t, factory)
+		})
+	}
+}
+
+// Test reconciles the key of the row and checks the writes and events.
+func (r *TableRow) Test(t *testing.T, factory Factory) {
+	t.Helper()
+
+	recorder := record.NewFakeRecorder(eventBufferSize)
+	fakes := Fakes{
+		Listers:  NewListers(r.Objects),
+		Client:   NewClientset(r.Objects, r.WithReactors...),
+		Recorder: recorder,
+		Stats:    &FakeStatsReporter{},
+	}
+	c := factory(t, r, fakes)
+
+	ctx := r.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = logging.WithLogger(ctx, zap.NewNop().Sugar())
+
+	if err := c.Reconcile(ctx, r.Key); (err != nil) != r.WantErr {
+		t.Errorf("Reconcile() error = %v, WantErr %v", err, r.WantErr)
+	}
+
+	var creates, updates, statusUpdates []runtime.Object
+	var deletes, patches []Action
+	for _, action := range fakes.Client.Actions() {
+		switch action.Verb {
+		case VerbCreate:
+			creates = append(creates, action.Object)
+		case VerbUpdate:
+			updates = append(updates, action.Object)
+		case VerbUpdateStatus:
+			statusUpdates = append(statusUpdates, action.Object)
+		case VerbDelete:
+			deletes = append(deletes, Action{Resource: action.Resource, Namespace: action.Namespace, Name: action.Name})
+		case VerbPatch:
+			patches = append(patches, Action{Resource: action.Resource, Namespace: action.Namespace, Name: action.Name, Patch: action.Patch})
+		}
+	}
+	if diff := cmp.Diff(r.WantCreates, creates); diff != "" {
+		t.Errorf("Unexpected creates (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(r.WantUpdates, updates); diff != "" {
+		t.Errorf("Unexpected updates (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(toUnstructured(t, r.WantStatusUpdates), toUnstructured(t, statusUpdates)); diff != "" {
+		t.Errorf("Unexpected status updates (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(r.WantDeletes, deletes); diff != "" {
+		t.Errorf("Unexpected deletes (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(r.WantPatches, patches); diff != "" {
+		t.Errorf("Unexpected patches (-want, +got): %s", diff)
+	}
+
+	var events []string
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	if diff := cmp.Diff(r.WantEvents, events); diff != "" {
+		t.Errorf("Unexpected events (-want, +got): %s", diff)
+	}
+
+	for _, check := range r.PostConditions {
+		check(t, r, fakes)
+	}
+}
+
+// Eventf formats an event as record.FakeRecorder does.
+func Eventf(eventtype, reason, messageFmt string, args ...interface{}) string {
+	return fmt.Sprintf(eventtype+" "+reason+" "+messageFmt, args...)
+}
+
+// toUnstructured converts typed and unstructured objects alike to their
+// unstructured content.
+func toUnstructured(t *testing.T, objs []runtime.Object) []map[string]interface{} {
+	t.Helper()
+	var us []map[string]interface{}
+	for _, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			t.Fatalf("ToUnstructured() = %v", err)
+		}
+		us = append(us, u)
+	}
+	return us
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scope restricts the controllers to a set of namespaces.
+package scope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+)
+
+// Scope is the set of namespaces the controllers act in.
+type Scope struct {
+	// Namespaces lists the namespaces the informers are limited to. When it
+	// is empty, the informers watch every namespace.
+	Namespaces []string
+	// Selector selects the namespaces, by label, among the ones watched.
+	Selector labels.Selector
+}
+
+// New makes a Scope from a comma separated list of namespaces and a label
+// selector, both of which may be empty. It returns nil when neither is set.
+func New(namespaces, selector string) (*Scope, error) {
+	s := &Scope{}
+	for _, namespace := range strings.Split(namespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			s.Namespaces = append(s.Namespaces, namespace)
+		}
+	}
+	if selector != "" {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the namespace selector %q: %v", selector, err)
+		}
+		s.Selector = sel
+	}
+	if len(s.Namespaces) == 0 && s.Selector == nil {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// Namespaced reports whether the informers are limited to a list of
+// namespaces. The controllers then only see the objects of one namespace at a
+// time, and need namespaced Roles only.
+func (s *Scope) Namespaced() bool {
+	return s != nil && len(s.Namespaces) > 0
+}
+
+// Allows reports whether the controllers may act in the namespace.
+func (s *Scope) Allows(namespaceLister corev1listers.NamespaceLister, namespace string) (bool, error) {
+	if s == nil {
+		return true, nil
+	}
+	if s.Namespaced() && !s.lists(namespace) {
+		return false, nil
+	}
+	if s.Selector == nil || s.Selector.Empty() {
+		return true, nil
+	}
+	ns, err := namespaceLister.Get(namespace)
+	if apierrs.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return s.Selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+func (s *Scope) lists(namespace string) bool {
+	for _, ns := range s.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Context returns a context carrying the Scope, with the injected informers
+// limited to the first namespace of the list.
+func (s *Scope) Context(ctx context.Context) context.Context {
+	if s.Namespaced() {
+		ctx = injection.WithNamespaceScope(ctx, s.Namespaces[0])
+	}
+	return WithScope(ctx, s)
+}
+
+// Constructors returns the controllers to run for the Scope. Every controller
+// runs on the informers of the first namespace of the list. The cluster
+// controllers run once, and find the informers of the other namespaces with
+// NamespaceContexts; the namespaced controllers run again for each other
+// namespace, on its informers.
+func (s *Scope) Constructors(cfg *rest.Config, cluster []injection.ControllerConstructor, namespaced ...injection.ControllerConstructor) []injection.ControllerConstructor {
+	all := make([]injection.ControllerConstructor, 0, len(cluster)+len(namespaced))
+	if !s.Namespaced() || len(s.Namespaces) == 1 {
+		return append(append(all, cluster...), namespaced...)
+	}
+
+	others := &namespaceInformers{cfg: cfg, namespaces: s.Namespaces[1:]}
+	for i := range cluster {
+		ctor := cluster[i]
+		all = append(all, func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+			return ctor(withNamespaceContexts(ctx, others.setup(ctx)), cmw)
+		})
+	}
+	all = append(all, namespaced...)
+	for n := range others.namespaces {
+		for i := range namespaced {
+			n, ctor := n, namespaced[i]
+			all = append(all, func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+				return ctor(others.setup(ctx)[n], cmw)
+			})
+		}
+	}
+
+	// sharedmain only starts the informers it made itself, so the informers
+	// of the other namespaces are started once every controller has added
+	// its event handlers.
+	last := all[len(all)-1]
+	all[len(all)-1] = func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		impl := last(ctx, cmw)
+		others.start(ctx)
+		return impl
+	}
+	return all
+}
+
+// namespaceInformers are the informers of the namespaces of the list but the
+// first, set up by the first controller to need them.
+type namespaceInformers struct {
+	cfg        *rest.Config
+	namespaces []string
+
+	once      sync.Once
+	contexts  []context.Context
+	informers []controller.Informer
+}
+
+// setup returns a context with the informers of each namespace, in order.
+func (n *namespaceInformers) setup(ctx context.Context) []context.Context {
+	n.once.Do(func() {
+		for _, namespace := range n.namespaces {
+			nsctx, informers := injection.Default.SetupInformers(injection.WithNamespaceScope(ctx, namespace), n.cfg)
+			n.contexts = append(n.contexts, nsctx)
+			n.informers = append(n.informers, informers...)
+		}
+	})
+	return n.contexts
+}
+
+func (n *namespaceInformers) start(ctx context.Context) {
+	n.setup(ctx)
+	if err := controller.StartInformers(ctx.Done(), n.informers...); err != nil {
+		logging.FromContext(ctx).Fatalf("Failed to start the informers of namespaces %v: %v", n.namespaces, err)
+	}
+}
+
+type namespaceContextsKey struct{}
+
+func withNamespaceContexts(ctx context.Context, contexts []context.Context) context.Context {
+	return context.WithValue(ctx, namespaceContextsKey{}, contexts)
+}
+
+// NamespaceContexts returns, to a cluster controller, a context with the
+// informers of each namespace of the list but the first, which its own
+// context has. It returns none when the informers watch every namespace or a
+// single one.
+func NamespaceContexts(ctx context.Context) []context.Context {
+	contexts, _ := ctx.Value(namespaceContextsKey{}).([]context.Context)
+	return contexts
+}
+
+type scopeKey struct{}
+
+// WithScope attaches the Scope to the context.
+func WithScope(ctx context.Context, s *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// FromContext returns the Scope attached to the context, or nil, which
+// allows every namespace.
+func FromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
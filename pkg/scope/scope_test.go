@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+)
+
+func TestAllows(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for name, labels := range map[string]map[string]string{
+		"team-a":      {"team": "a"},
+		"team-b":      {"team": "b"},
+		"kube-system": nil,
+	} {
+		indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})
+	}
+	lister := corev1listers.NewNamespaceLister(indexer)
+
+	tests := map[string]struct {
+		namespaces string
+		selector   string
+		want       map[string]bool
+	}{
+		"unscoped": {
+			want: map[string]bool{"team-a": true, "kube-system": true, "missing": true},
+		},
+		"namespaces": {
+			namespaces: "team-a, team-b",
+			want:       map[string]bool{"team-a": true, "team-b": true, "kube-system": false},
+		},
+		"selector": {
+			selector: "team",
+			want:     map[string]bool{"team-a": true, "team-b": true, "kube-system": false, "missing": false},
+		},
+		"both": {
+			namespaces: "team-a,kube-system",
+			selector:   "team=a",
+			want:       map[string]bool{"team-a": true, "team-b": false, "kube-system": false},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := New(tc.namespaces, tc.selector)
+			if err != nil {
+				t.Fatalf("New() = %v", err)
+			}
+			for namespace, want := range tc.want {
+				if got, err := s.Allows(lister, namespace); err != nil {
+					t.Errorf("Allows(%q) = %v", namespace, err)
+				} else if got != want {
+					t.Errorf("Allows(%q) = %v, wanted %v", namespace, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewInvalidSelector(t *testing.T) {
+	if _, err := New("", "team in (a"); err == nil {
+		t.Error("New() = nil, wanted an error")
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	ctor := func(context.Context, configmap.Watcher) *controller.Impl { return nil }
+	cluster := []injection.ControllerConstructor{ctor}
+
+	tests := map[string]struct {
+		namespaces string
+		want       int
+	}{
+		"every namespace":    {want: 3},
+		"single namespace":   {namespaces: "a", want: 3},
+		"list of namespaces": {namespaces: "a,b,c", want: 7},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := New(tc.namespaces, "")
+			if err != nil {
+				t.Fatalf("New() = %v", err)
+			}
+			// The cluster controller runs once, the namespaced ones once per
+			// namespace.
+			if got := len(s.Constructors(&rest.Config{}, cluster, ctor, ctor)); got != tc.want {
+				t.Errorf("Constructors() = %d constructors, wanted %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNamespaceContexts(t *testing.T) {
+	if got := NamespaceContexts(context.Background()); got != nil {
+		t.Errorf("NamespaceContexts() = %v, wanted none", got)
+	}
+	contexts := []context.Context{context.Background()}
+	if got := NamespaceContexts(withNamespaceContexts(context.Background(), contexts)); len(got) != 1 {
+		t.Errorf("NamespaceContexts() = %v, wanted %v", got, contexts)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"contrib.go.opencensus.io/exporter/stackdriver"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/tracing/config"
+)
+
+const (
+	// ConfigName is the name of the ConfigMap that configures tracing.
+	ConfigName = config.ConfigName
+
+	// Attributes set on every span.
+	GVRAttribute       = "gvr"
+	NamespaceAttribute = "namespace"
+	NameAttribute      = "name"
+)
+
+// Tracer applies the tracing configuration to the process wide OpenCensus
+// tracer. knative.dev/pkg/tracing does the same, but needs the zipkin
+// exporter, which is not vendored; only the Stackdriver backend is exported.
+type Tracer struct {
+	logger *zap.SugaredLogger
+
+	m        sync.Mutex
+	cfg      *config.Config
+	exporter *stackdriver.Exporter
+}
+
+// NewTracer returns a Tracer that never samples until it is configured.
+func NewTracer(logger *zap.SugaredLogger) *Tracer {
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+	return &Tracer{logger: logger}
+}
+
+// UpdateFromConfigMap is a configmap.Observer for the config-tracing ConfigMap.
+func (t *Tracer) UpdateFromConfigMap(cm *corev1.ConfigMap) {
+	cfg, err := config.NewTracingConfigFromConfigMap(cm)
+	if err != nil {
+		t.logger.Errorw("failed to parse the tracing config", zap.Error(err))
+		return
+	}
+	if err := t.ApplyConfig(cfg); err != nil {
+		t.logger.Errorw("failed to apply the tracing config", zap.Error(err))
+	}
+}
+
+// ApplyConfig sets up the exporter and sampler for the configuration.
+func (t *Tracer) ApplyConfig(cfg *config.Config) error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.cfg != nil && t.cfg.Equals(cfg) {
+		return nil
+	}
+
+	var exporter *stackdriver.Exporter
+	switch cfg.Backend {
+	case config.Stackdriver:
+		var err error
+		exporter, err = stackdriver.NewExporter(stackdriver.Options{
+			ProjectID: cfg.StackdriverProjectID,
+		})
+		if err != nil {
+			return err
+		}
+	case config.Zipkin:
+		return fmt.Errorf("tracing backend %q is not supported", cfg.Backend)
+	}
+
+	// Register the new exporter before the old one goes, so no spans are lost.
+	if exporter != nil {
+		trace.RegisterExporter(exporter)
+	}
+	if t.exporter != nil {
+		trace.UnregisterExporter(t.exporter)
+		t.exporter.Flush()
+	}
+	t.exporter = exporter
+	t.cfg = cfg
+
+	trace.ApplyConfig(trace.Config{DefaultSampler: sampler(cfg)})
+	return nil
+}
+
+// Flush exports the spans that are still buffered.
+func (t *Tracer) Flush() {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.exporter != nil {
+		t.exporter.Flush()
+	}
+}
+
+func sampler(cfg *config.Config) trace.Sampler {
+	switch {
+	case cfg.Backend == config.None:
+		return trace.NeverSample()
+	case cfg.Debug:
+		return trace.AlwaysSample()
+	default:
+		return trace.ProbabilitySampler(cfg.SampleRate)
+	}
+}
+
+// StartSpan starts a span for an operation on the named resource.
+func StartSpan(ctx context.Context, operation string, gvr schema.GroupVersionResource, namespace, name string) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, operation)
+	span.AddAttributes(
+		trace.StringAttribute(GVRAttribute, gvr.String()),
+		trace.StringAttribute(NamespaceAttribute, namespace),
+		trace.StringAttribute(NameAttribute, name),
+	)
+	return ctx, span
+}
+
+// EndSpan records the error, if any, and ends the span.
+func EndSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
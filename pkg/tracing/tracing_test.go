@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/tracing/config"
+)
+
+type fakeExporter struct {
+	spans []*trace.SpanData
+}
+
+func (e *fakeExporter) ExportSpan(s *trace.SpanData) {
+	e.spans = append(e.spans, s)
+}
+
+func TestSampler(t *testing.T) {
+	tests := map[string]struct {
+		cfg  *config.Config
+		want bool
+	}{
+		"none": {
+			cfg:  &config.Config{Backend: config.None, Debug: true},
+			want: false,
+		},
+		"debug": {
+			cfg:  &config.Config{Backend: config.Stackdriver, Debug: true},
+			want: true,
+		},
+		"never": {
+			cfg:  &config.Config{Backend: config.Stackdriver, SampleRate: 0},
+			want: false,
+		},
+		"always": {
+			cfg:  &config.Config{Backend: config.Stackdriver, SampleRate: 1},
+			want: true,
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			got := sampler(tc.cfg)(trace.SamplingParameters{}).Sample
+			if got != tc.want {
+				t.Errorf("sampler() = %v, wanted %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateFromConfigMap(t *testing.T) {
+	tracer := NewTracer(zap.NewNop().Sugar())
+	tracer.UpdateFromConfigMap(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName},
+		Data:       map[string]string{"backend": "none"},
+	})
+	if tracer.cfg == nil || tracer.cfg.Backend != config.None {
+		t.Errorf("cfg = %+v, wanted the none backend", tracer.cfg)
+	}
+
+	// An unsupported backend leaves the configuration as it was.
+	if err := tracer.ApplyConfig(&config.Config{Backend: config.Zipkin, ZipkinEndpoint: "http://zipkin"}); err == nil {
+		t.Error("ApplyConfig() = nil, wanted an error for zipkin")
+	}
+	if tracer.cfg.Backend != config.None {
+		t.Errorf("cfg = %+v, wanted the none backend", tracer.cfg)
+	}
+}
+
+func TestStartSpan(t *testing.T) {
+	exporter := &fakeExporter{}
+	trace.RegisterExporter(exporter)
+	defer trace.UnregisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	defer trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+
+	gvr := schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "services"}
+	_, span := StartSpan(context.Background(), "Reconcile", gvr, "default", "hello")
+	EndSpan(span, errors.New("boom"))
+
+	if len(exporter.spans) != 1 {
+		t.Fatalf("exported %d spans, wanted 1", len(exporter.spans))
+	}
+	got := exporter.spans[0]
+	want := map[string]interface{}{
+		GVRAttribute:       gvr.String(),
+		NamespaceAttribute: "default",
+		NameAttribute:      "hello",
+	}
+	for k, v := range want {
+		if got.Attributes[k] != v {
+			t.Errorf("attribute %s = %v, wanted %v", k, got.Attributes[k], v)
+		}
+	}
+	if got.Status.Message != "boom" {
+		t.Errorf("status = %+v, wanted the error", got.Status)
+	}
+}